@@ -28,6 +28,10 @@ const (
 	ModeFull      = "full"
 	ModeValidator = "validator"
 	ModeSeed      = "seed"
+
+	EvictionPolicyPriority     = "priority"
+	EvictionPolicyOldestHeight = "oldest-height"
+	EvictionPolicyFIFO         = "fifo"
 )
 
 // NOTE: Most of the structs & relevant comments + the
@@ -767,6 +771,13 @@ type MempoolConfig struct {
 	// max-txs-bytes=5MB, mempool will only accept 5 transactions).
 	MaxTxsBytes int64 `mapstructure:"max-txs-bytes"`
 
+	// MaxMemoryBytes, if non-zero, limits the estimated memory held by the
+	// mempool: the bytes of all txs, including the pending ones, the memory
+	// held for each of them besides their bytes, and the caches. Txs are
+	// evicted, or incoming ones rejected, to stay within it, like for
+	// MaxTxsBytes. The estimate is a lower bound of the memory actually used.
+	MaxMemoryBytes int64 `mapstructure:"max-memory-bytes"`
+
 	// Size of the cache (used to filter transactions we saw earlier) in transactions
 	CacheSize int `mapstructure:"cache-size"`
 
@@ -775,6 +786,25 @@ type MempoolConfig struct {
 	// valid again in the future.
 	KeepInvalidTxsInCache bool `mapstructure:"keep-invalid-txs-in-cache"`
 
+	// CacheTTL, if non-zero, defines how long a transaction is remembered by
+	// the cache. A transaction resubmitted after its entry expired is checked
+	// again.
+	CacheTTL time.Duration `mapstructure:"cache-ttl"`
+
+	// RejectionCacheSize, if non-zero, defines the number of transactions
+	// rejected by the application's CheckTx whose response is remembered, so
+	// that resubmissions are answered without executing CheckTx again. The
+	// rejections are forgotten on every block, or after CacheTTL if it is set.
+	// It has no effect if KeepInvalidTxsInCache is set.
+	RejectionCacheSize int `mapstructure:"rejection-cache-size"`
+
+	// RejectionCacheCodes defines the CheckTx response codes, in any
+	// codespace, of the rejections the rejection cache remembers. They must
+	// only be codes of permanent rejections, e.g. a malformed transaction or an
+	// invalid signature: a rejection that depends on the application state,
+	// e.g. a nonce gap, may no longer hold before the next block.
+	RejectionCacheCodes []uint32 `mapstructure:"rejection-cache-codes"`
+
 	// Maximum size of a single transaction
 	// NOTE: the max size of a tx transmitted over the network is {max-tx-bytes}.
 	MaxTxBytes int `mapstructure:"max-tx-bytes"`
@@ -818,6 +848,67 @@ type MempoolConfig struct {
 	PendingTTLDuration time.Duration `mapstructure:"pending-ttl-duration"`
 
 	PendingTTLNumBlocks int64 `mapstructure:"pending-ttl-num-blocks"`
+
+	// RecheckRetryTimeout defines how long the re-CheckTx of the transactions
+	// that could not be rechecked after a block, because the app connection
+	// failed, is retried in the background before the mempool is marked
+	// degraded. The transactions are excluded from reaping meanwhile.
+	RecheckRetryTimeout time.Duration `mapstructure:"recheck-retry-timeout"`
+
+	// EvictionPolicy defines which transactions are evicted to make room for
+	// an incoming transaction when the mempool is full:
+	//   - "priority" evicts the lowest priority transactions, and only those
+	//     of lower priority than the incoming transaction (default)
+	//   - "oldest-height" evicts the transactions validated at the oldest
+	//     height, and only those from an earlier height than the incoming
+	//     transaction
+	//   - "fifo" evicts the earliest received transactions, regardless of
+	//     their priority
+	// An empty policy, e.g. in a config file predating this option, is the
+	// priority policy.
+	EvictionPolicy string `mapstructure:"eviction-policy"`
+
+	// WalPath, if set, enables persisting the mempool's transactions in a
+	// write-ahead log in this directory, so they are replayed through CheckTx
+	// when the node restarts. Private transactions are never persisted.
+	WalPath string `mapstructure:"wal-dir"`
+
+	// CheckTxLatencyThreshold, if non-zero, enables a circuit breaker on
+	// CheckTx. Once the moving average of the application's CheckTx latency
+	// exceeds the threshold, or a CheckTx call fails, new transactions are
+	// rejected with ErrAppOverloaded for CheckTxBreakerCooldown. Then a single
+	// transaction is let through to probe whether the application recovered.
+	CheckTxLatencyThreshold time.Duration `mapstructure:"check-tx-latency-threshold"`
+
+	// CheckTxBreakerCooldown defines how long the CheckTx circuit breaker
+	// rejects transactions before probing the application again.
+	CheckTxBreakerCooldown time.Duration `mapstructure:"check-tx-breaker-cooldown"`
+
+	// PeerTxRateLimit, if non-zero, limits the number of transactions per
+	// second a single peer may submit to the mempool. Transactions over the
+	// limit are rejected before CheckTx is executed.
+	PeerTxRateLimit float64 `mapstructure:"peer-tx-rate-limit"`
+
+	// PeerBytesRateLimit, if non-zero, limits the number of transaction bytes
+	// per second a single peer may submit to the mempool. It must be at least
+	// MaxTxBytes.
+	PeerBytesRateLimit int64 `mapstructure:"peer-bytes-rate-limit"`
+
+	// RPCTxRateLimit, if non-zero, limits the number of transactions per
+	// second submitted to the mempool via RPC, across all RPC clients.
+	RPCTxRateLimit float64 `mapstructure:"rpc-tx-rate-limit"`
+
+	// RPCBytesRateLimit, if non-zero, limits the number of transaction bytes
+	// per second submitted to the mempool via RPC, across all RPC clients. It
+	// must be at least MaxTxBytes.
+	RPCBytesRateLimit int64 `mapstructure:"rpc-bytes-rate-limit"`
+
+	// PublishTxEvents enables publishing a MempoolTxAdded or MempoolTxRemoved
+	// event every time a transaction enters or leaves the mempool. The events
+	// can be consumed with the subscribe and events RPC endpoints; the latter
+	// returns a cursor for every event so consumers can resume after a
+	// disconnect. Events are dropped if the event bus falls behind.
+	PublishTxEvents bool `mapstructure:"publish-tx-events"`
 }
 
 // DefaultMempoolConfig returns a default configuration for the Tendermint mempool.
@@ -828,7 +919,10 @@ func DefaultMempoolConfig() *MempoolConfig {
 		// ABCI Recheck
 		Size:                         5000,
 		MaxTxsBytes:                  1024 * 1024 * 1024, // 1GB
+		MaxMemoryBytes:               0,
 		CacheSize:                    10000,
+		CacheTTL:                     0 * time.Second,
+		RejectionCacheSize:           0,
 		MaxTxBytes:                   1024 * 1024, // 1MB
 		TTLDuration:                  0 * time.Second,
 		TTLNumBlocks:                 0,
@@ -839,9 +933,29 @@ func DefaultMempoolConfig() *MempoolConfig {
 		MaxPendingTxsBytes:           1024 * 1024 * 1024, // 1GB
 		PendingTTLDuration:           0 * time.Second,
 		PendingTTLNumBlocks:          0,
+		RecheckRetryTimeout:          1 * time.Second,
+		EvictionPolicy:               EvictionPolicyPriority,
+		CheckTxLatencyThreshold:      0,
+		CheckTxBreakerCooldown:       5 * time.Second,
+		WalPath:                      "",
+		PeerTxRateLimit:              0,
+		PeerBytesRateLimit:           0,
+		RPCTxRateLimit:               0,
+		RPCBytesRateLimit:            0,
+		PublishTxEvents:              false,
 	}
 }
 
+// WalDir returns the full path to the mempool's write-ahead log directory.
+func (cfg *MempoolConfig) WalDir() string {
+	return rootify(cfg.WalPath, cfg.RootDir)
+}
+
+// WalEnabled returns true if the mempool's write-ahead log is enabled.
+func (cfg *MempoolConfig) WalEnabled() bool {
+	return cfg.WalPath != ""
+}
+
 // TestMempoolConfig returns a configuration for testing the Tendermint mempool
 func TestMempoolConfig() *MempoolConfig {
 	cfg := DefaultMempoolConfig()
@@ -858,9 +972,18 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.MaxTxsBytes < 0 {
 		return errors.New("max-txs-bytes can't be negative")
 	}
+	if cfg.MaxMemoryBytes < 0 {
+		return errors.New("max-memory-bytes can't be negative")
+	}
 	if cfg.CacheSize < 0 {
 		return errors.New("cache-size can't be negative")
 	}
+	if cfg.CacheTTL < 0 {
+		return errors.New("cache-ttl can't be negative")
+	}
+	if cfg.RejectionCacheSize < 0 {
+		return errors.New("rejection-cache-size can't be negative")
+	}
 	if cfg.MaxTxBytes < 0 {
 		return errors.New("max-tx-bytes can't be negative")
 	}
@@ -876,6 +999,38 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.CheckTxErrorThreshold < 0 {
 		return errors.New("check-tx-error-threshold can't be negative")
 	}
+	if cfg.RecheckRetryTimeout < 0 {
+		return errors.New("recheck-retry-timeout can't be negative")
+	}
+	switch cfg.EvictionPolicy {
+	case "", EvictionPolicyPriority, EvictionPolicyOldestHeight, EvictionPolicyFIFO:
+	default:
+		return fmt.Errorf("unknown eviction-policy %q", cfg.EvictionPolicy)
+	}
+	if cfg.CheckTxLatencyThreshold < 0 {
+		return errors.New("check-tx-latency-threshold can't be negative")
+	}
+	if cfg.CheckTxBreakerCooldown < 0 {
+		return errors.New("check-tx-breaker-cooldown can't be negative")
+	}
+	if cfg.PeerTxRateLimit < 0 {
+		return errors.New("peer-tx-rate-limit can't be negative")
+	}
+	if cfg.PeerBytesRateLimit < 0 {
+		return errors.New("peer-bytes-rate-limit can't be negative")
+	}
+	if cfg.PeerBytesRateLimit > 0 && cfg.PeerBytesRateLimit < int64(cfg.MaxTxBytes) {
+		return errors.New("peer-bytes-rate-limit can't be less than max-tx-bytes")
+	}
+	if cfg.RPCTxRateLimit < 0 {
+		return errors.New("rpc-tx-rate-limit can't be negative")
+	}
+	if cfg.RPCBytesRateLimit < 0 {
+		return errors.New("rpc-bytes-rate-limit can't be negative")
+	}
+	if cfg.RPCBytesRateLimit > 0 && cfg.RPCBytesRateLimit < int64(cfg.MaxTxBytes) {
+		return errors.New("rpc-bytes-rate-limit can't be less than max-tx-bytes")
+	}
 
 	return nil
 }
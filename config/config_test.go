@@ -86,7 +86,10 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 	fieldsToTest := []string{
 		"Size",
 		"MaxTxsBytes",
+		"MaxMemoryBytes",
 		"CacheSize",
+		"CacheTTL",
+		"RejectionCacheSize",
 		"MaxTxBytes",
 	}
 
@@ -95,6 +98,12 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	// an empty eviction policy is the default one
+	cfg.EvictionPolicy = ""
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.EvictionPolicy = "lru"
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestStateSyncConfigValidateBasic(t *testing.T) {
@@ -372,6 +372,14 @@ size = {{ .Mempool.Size }}
 # max-txs-bytes=5MB, mempool will only accept 5 transactions).
 max-txs-bytes = {{ .Mempool.MaxTxsBytes }}
 
+# If non-zero, limit the estimated memory held by the mempool: the bytes of
+# all txs, including the pending ones, the memory held for each of them besides
+# their bytes, and the caches. Txs are evicted, or incoming ones rejected, to
+# stay within it, like for max-txs-bytes. The estimate is a lower bound of the
+# memory actually used, so leave headroom below the memory available to the
+# node. See the mempool_memory_bytes metric for the current estimate.
+max-memory-bytes = {{ .Mempool.MaxMemoryBytes }}
+
 # Size of the cache (used to filter transactions we saw earlier) in transactions
 cache-size = {{ .Mempool.CacheSize }}
 
@@ -380,6 +388,23 @@ cache-size = {{ .Mempool.CacheSize }}
 # again in the future.
 keep-invalid-txs-in-cache = {{ .Mempool.KeepInvalidTxsInCache }}
 
+# If non-zero, how long a transaction is remembered by the cache. A transaction
+# resubmitted after its entry expired is checked again.
+cache-ttl = "{{ .Mempool.CacheTTL }}"
+
+# Number of transactions rejected by the application's CheckTx whose response
+# is remembered, so that resubmissions are answered without executing CheckTx
+# again. Rejections are forgotten on every block, or after cache-ttl if it is
+# set. Set to 0 to disable. Has no effect if keep-invalid-txs-in-cache is true.
+rejection-cache-size = {{ .Mempool.RejectionCacheSize }}
+
+# CheckTx response codes, in any codespace, of the rejections remembered by the
+# rejection cache. Only list the codes of permanent rejections, e.g. a malformed
+# transaction or an invalid signature: a rejection that depends on the
+# application state, e.g. a nonce gap, may no longer hold before the next block.
+# Other rejections are never cached.
+rejection-cache-codes = [{{ range $i, $e := .Mempool.RejectionCacheCodes }}{{if $i}}, {{end}}{{ $e }}{{end}}]
+
 # Maximum size of a single transaction.
 # NOTE: the max size of a tx transmitted over the network is {max-tx-bytes}.
 max-tx-bytes = {{ .Mempool.MaxTxBytes }}
@@ -419,6 +444,70 @@ pending-ttl-duration = "{{ .Mempool.PendingTTLDuration }}"
 
 pending-ttl-num-blocks = {{ .Mempool.PendingTTLNumBlocks }}
 
+# recheck-retry-timeout defines how long the re-CheckTx of the transactions that
+# could not be rechecked after a block, because the app connection failed, is
+# retried in the background before the mempool is marked degraded. The
+# transactions are excluded from reaping meanwhile.
+recheck-retry-timeout = "{{ .Mempool.RecheckRetryTimeout }}"
+
+# eviction-policy defines which transactions are evicted to make room for an
+# incoming transaction when the mempool is full:
+#   - "priority" evicts the lowest priority transactions, and only those of
+#     lower priority than the incoming transaction (default)
+#   - "oldest-height" evicts the transactions validated at the oldest height,
+#     and only those from an earlier height than the incoming transaction
+#   - "fifo" evicts the earliest received transactions, regardless of their
+#     priority
+# An empty policy is the priority policy.
+eviction-policy = "{{ .Mempool.EvictionPolicy }}"
+
+# wal-dir, if set, enables persisting the mempool's transactions in a
+# write-ahead log in this directory (relative to the home directory), so they
+# are replayed through CheckTx when the node restarts. The log is compacted in
+# the background once removed transactions make up most of it. Private
+# transactions are never persisted.
+wal-dir = "{{ js .Mempool.WalPath }}"
+
+# check-tx-latency-threshold, if non-zero, enables a circuit breaker on CheckTx.
+# Once the moving average of the application's CheckTx latency exceeds the
+# threshold, or a CheckTx call fails, new transactions are rejected for
+# check-tx-breaker-cooldown. Then a single transaction is let through to probe
+# whether the application recovered.
+check-tx-latency-threshold = "{{ .Mempool.CheckTxLatencyThreshold }}"
+
+# How long the CheckTx circuit breaker rejects new transactions once it opened,
+# before letting a probe through. Only used if check-tx-latency-threshold is
+# non-zero.
+check-tx-breaker-cooldown = "{{ .Mempool.CheckTxBreakerCooldown }}"
+
+# peer-tx-rate-limit, if non-zero, limits the number of transactions per second
+# a single peer may submit to the mempool, with a burst of one second's worth.
+# Transactions over the limit are rejected before CheckTx is executed.
+peer-tx-rate-limit = {{ .Mempool.PeerTxRateLimit }}
+
+# peer-bytes-rate-limit, if non-zero, limits the number of transaction bytes per
+# second a single peer may submit to the mempool, with a burst of one second's
+# worth. It must be at least max-tx-bytes.
+peer-bytes-rate-limit = {{ .Mempool.PeerBytesRateLimit }}
+
+# rpc-tx-rate-limit and rpc-bytes-rate-limit, if non-zero, limit the number of
+# transactions and of transaction bytes per second submitted via the broadcast
+# RPC endpoints, with a burst of one second's worth. The limits are shared by
+# all RPC clients. rpc-bytes-rate-limit must be at least max-tx-bytes.
+# Transactions the node checks on its own, e.g. replayed from the WAL or
+# included in a proposed block, are never limited.
+rpc-tx-rate-limit = {{ .Mempool.RPCTxRateLimit }}
+rpc-bytes-rate-limit = {{ .Mempool.RPCBytesRateLimit }}
+
+# publish-tx-events enables publishing a MempoolTxAdded or MempoolTxRemoved event
+# every time a transaction enters or leaves the mempool, with the reason of the
+# removal. The events can be consumed with the subscribe and events RPC
+# endpoints; the latter returns a cursor for every event so consumers can
+# resume after a disconnect. Note that at high transaction rates the events
+# crowd other events out of the RPC event log window, and that events are
+# dropped, never delaying the mempool, if the event bus falls behind.
+publish-tx-events = {{ .Mempool.PublishTxEvents }}
+
 #######################################################
 ###         State Sync Configuration Options        ###
 #######################################################
@@ -5,6 +5,7 @@ import (
 
 	abciclient "github.com/tendermint/tendermint/abci/client"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/internal/libs/clist"
 	"github.com/tendermint/tendermint/internal/mempool"
 	"github.com/tendermint/tendermint/internal/proxy"
@@ -52,6 +53,17 @@ func (emptyMempool) FlushAppConn(ctx context.Context) error { return nil }
 func (emptyMempool) TxsAvailable() <-chan struct{}          { return make(chan struct{}) }
 func (emptyMempool) EnableTxsAvailable()                    {}
 func (emptyMempool) SizeBytes() int64                       { return 0 }
+func (emptyMempool) IsDegraded() bool                       { return false }
+func (emptyMempool) CheckTxBreakerState() string            { return "closed" }
+func (emptyMempool) UpdateConfig(func(*config.MempoolConfig)) error {
+	return nil
+}
+func (emptyMempool) ExportTxs() types.Txs                  { return types.Txs{} }
+func (emptyMempool) ForEachTx(int, func(types.Tx) bool)    {}
+func (emptyMempool) ForEachExportedTx(func(types.Tx) bool) {}
+func (emptyMempool) ImportTxs(context.Context, types.Txs) (int, error) {
+	return 0, nil
+}
 
 func (emptyMempool) TxsFront() *clist.CElement    { return nil }
 func (emptyMempool) TxsWaitChan() <-chan struct{} { return nil }
@@ -195,6 +195,14 @@ func (b *EventBus) PublishEventValidatorSetUpdates(data types.EventDataValidator
 	return b.Publish(types.EventValidatorSetUpdatesValue, data)
 }
 
+func (b *EventBus) PublishEventMempoolTxAdded(data types.EventDataMempoolTx) error {
+	return b.Publish(types.EventMempoolTxAddedValue, data)
+}
+
+func (b *EventBus) PublishEventMempoolTxRemoved(data types.EventDataMempoolTx) error {
+	return b.Publish(types.EventMempoolTxRemovedValue, data)
+}
+
 func (b *EventBus) PublishEventEvidenceValidated(evidence types.EventDataEvidenceValidated) error {
 	return b.Publish(types.EventEvidenceValidatedValue, evidence)
 }
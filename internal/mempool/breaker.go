@@ -0,0 +1,177 @@
+package mempool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/types"
+)
+
+// breakerState defines the state of a circuitBreaker. The values are reported
+// by the CheckTxBreakerState metric.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// breakerLatencyWeight is the weight of the latest CheckTx latency in the
+// moving average tracked by a closed circuitBreaker.
+const breakerLatencyWeight = 0.2
+
+// circuitBreaker sheds CheckTx load while the application is slow to respond.
+//
+// While closed, it tracks an exponentially weighted moving average of the
+// CheckTx latency. Once the average exceeds the threshold, or a call fails, the
+// breaker opens and rejects all new transactions with ErrAppOverloaded. After
+// the cooldown it lets a single probe through (half-open): if the probe is fast
+// the breaker closes again, otherwise it re-opens for another cooldown.
+//
+// Calls that were canceled by their caller say nothing about the application
+// and are reported via abort instead of done.
+type circuitBreaker struct {
+	mtx sync.Mutex
+
+	threshold time.Duration
+	cooldown  time.Duration
+
+	state    breakerState
+	avg      time.Duration
+	openedAt time.Time
+	probing  bool
+
+	// failure is the error of the CheckTx call that opened the breaker, or nil
+	// if it was opened because of the latency.
+	failure error
+
+	now func() time.Time
+}
+
+// newCircuitBreaker returns a breaker for the thresholds in cfg, or nil if the
+// breaker is disabled.
+func newCircuitBreaker(cfg *config.MempoolConfig) *circuitBreaker {
+	if cfg.CheckTxLatencyThreshold == 0 {
+		return nil
+	}
+
+	return &circuitBreaker{
+		threshold: cfg.CheckTxLatencyThreshold,
+		cooldown:  cfg.CheckTxBreakerCooldown,
+		now:       time.Now,
+	}
+}
+
+// allow returns ErrAppOverloaded if a CheckTx call must not be executed. If it
+// returns nil, the caller must report the outcome of the call via done or
+// abort.
+func (cb *circuitBreaker) allow() error {
+	if cb == nil {
+		return nil
+	}
+
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	switch cb.state {
+	case breakerOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cooldown {
+			return cb.overloaded()
+		}
+		cb.state = breakerHalfOpen
+		cb.probing = true
+		return nil
+
+	case breakerHalfOpen:
+		if cb.probing {
+			return cb.overloaded()
+		}
+		cb.probing = true
+		return nil
+	}
+
+	return nil
+}
+
+// done records the latency of a CheckTx call allowed by allow, and the error
+// of the application or of the connection to it, if the call failed. It
+// returns the state of the breaker after the call.
+func (cb *circuitBreaker) done(latency time.Duration, err error) breakerState {
+	if cb == nil {
+		return breakerClosed
+	}
+
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	switch cb.state {
+	case breakerClosed:
+		if err != nil {
+			cb.open(err)
+			break
+		}
+		cb.avg = time.Duration(breakerLatencyWeight*float64(latency) + (1-breakerLatencyWeight)*float64(cb.avg))
+		if cb.avg > cb.threshold {
+			cb.open(nil)
+		}
+
+	case breakerHalfOpen:
+		cb.probing = false
+		switch {
+		case err != nil:
+			cb.open(err)
+		case latency > cb.threshold:
+			cb.avg = latency
+			cb.open(nil)
+		default:
+			cb.state = breakerClosed
+			cb.avg = latency
+		}
+	}
+
+	return cb.state
+}
+
+// abort releases a CheckTx call allowed by allow without recording its
+// outcome, e.g. because the caller canceled it. It returns the state of the
+// breaker.
+func (cb *circuitBreaker) abort() breakerState {
+	if cb == nil {
+		return breakerClosed
+	}
+
+	cb.mtx.Lock()
+	defer cb.mtx.Unlock()
+
+	// let another probe through
+	if cb.state == breakerHalfOpen {
+		cb.probing = false
+	}
+	return cb.state
+}
+
+func (cb *circuitBreaker) open(failure error) {
+	cb.state = breakerOpen
+	cb.openedAt = cb.now()
+	cb.failure = failure
+}
+
+func (cb *circuitBreaker) overloaded() error {
+	if cb.failure != nil {
+		return types.ErrAppOverloaded{Threshold: cb.threshold, Failure: cb.failure}
+	}
+	return types.ErrAppOverloaded{Latency: cb.avg, Threshold: cb.threshold}
+}
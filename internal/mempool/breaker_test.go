@@ -0,0 +1,123 @@
+package mempool
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/types"
+)
+
+var errAppFailed = errors.New("app failed")
+
+func newTestCircuitBreaker(threshold, cooldown time.Duration) (*circuitBreaker, *time.Time) {
+	cfg := config.TestMempoolConfig()
+	cfg.CheckTxLatencyThreshold = threshold
+	cfg.CheckTxBreakerCooldown = cooldown
+
+	now := time.Now()
+	cb := newCircuitBreaker(cfg)
+	if cb != nil {
+		cb.now = func() time.Time { return now }
+	}
+	return cb, &now
+}
+
+func requireOverloaded(t *testing.T, err error) {
+	t.Helper()
+
+	var ovErr types.ErrAppOverloaded
+	require.True(t, errors.As(err, &ovErr), "expected ErrAppOverloaded, got %v", err)
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	cb, _ := newTestCircuitBreaker(0, time.Second)
+	require.Nil(t, cb)
+
+	require.NoError(t, cb.allow())
+	require.Equal(t, breakerClosed, cb.done(time.Hour, errAppFailed))
+	require.NoError(t, cb.allow())
+}
+
+func TestCircuitBreakerOpensOnLatency(t *testing.T) {
+	cb, now := newTestCircuitBreaker(100*time.Millisecond, time.Second)
+
+	// a single slow call does not move the average above the threshold
+	require.NoError(t, cb.allow())
+	require.Equal(t, breakerClosed, cb.done(10*time.Millisecond, nil))
+	require.NoError(t, cb.allow())
+	require.Equal(t, breakerClosed, cb.done(200*time.Millisecond, nil))
+
+	// sustained slow calls do
+	state := breakerClosed
+	for i := 0; i < 10 && state == breakerClosed; i++ {
+		require.NoError(t, cb.allow())
+		state = cb.done(200*time.Millisecond, nil)
+	}
+	require.Equal(t, breakerOpen, state)
+	requireOverloaded(t, cb.allow())
+
+	// after the cooldown a single probe is let through
+	*now = now.Add(time.Second)
+	require.NoError(t, cb.allow())
+	requireOverloaded(t, cb.allow())
+
+	// a slow probe re-opens the breaker
+	require.Equal(t, breakerOpen, cb.done(200*time.Millisecond, nil))
+	requireOverloaded(t, cb.allow())
+
+	// a fast probe closes it
+	*now = now.Add(time.Second)
+	require.NoError(t, cb.allow())
+	require.Equal(t, breakerClosed, cb.done(10*time.Millisecond, nil))
+	require.NoError(t, cb.allow())
+	require.NoError(t, cb.allow())
+}
+
+func TestCircuitBreakerOpensOnFailure(t *testing.T) {
+	cb, now := newTestCircuitBreaker(100*time.Millisecond, time.Second)
+
+	require.NoError(t, cb.allow())
+	require.Equal(t, breakerOpen, cb.done(time.Millisecond, errAppFailed))
+	requireOverloaded(t, cb.allow())
+
+	// the error reports the failure rather than the latency
+	var ovErr types.ErrAppOverloaded
+	require.ErrorAs(t, cb.allow(), &ovErr)
+	require.Equal(t, errAppFailed, ovErr.Failure)
+	require.Zero(t, ovErr.Latency)
+
+	// a failed probe re-opens the breaker
+	*now = now.Add(time.Second)
+	require.NoError(t, cb.allow())
+	require.Equal(t, breakerOpen, cb.done(time.Millisecond, errAppFailed))
+
+	*now = now.Add(time.Second)
+	require.NoError(t, cb.allow())
+	require.Equal(t, breakerClosed, cb.done(time.Millisecond, nil))
+}
+
+func TestCircuitBreakerAbort(t *testing.T) {
+	cb, now := newTestCircuitBreaker(100*time.Millisecond, time.Second)
+
+	// aborted calls neither open the breaker nor move the average
+	for i := 0; i < 10; i++ {
+		require.NoError(t, cb.allow())
+		require.Equal(t, breakerClosed, cb.abort())
+	}
+	require.Zero(t, cb.avg)
+
+	require.NoError(t, cb.allow())
+	require.Equal(t, breakerOpen, cb.done(time.Millisecond, errAppFailed))
+
+	// an aborted probe lets another probe through, without closing the breaker
+	*now = now.Add(time.Second)
+	require.NoError(t, cb.allow())
+	require.Equal(t, breakerHalfOpen, cb.abort())
+	require.NoError(t, cb.allow())
+	requireOverloaded(t, cb.allow())
+	require.Equal(t, breakerClosed, cb.done(time.Millisecond, nil))
+}
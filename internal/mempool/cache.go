@@ -3,7 +3,10 @@ package mempool
 import (
 	"container/list"
 	"sync"
+	"time"
 
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -31,15 +34,32 @@ var _ TxCache = (*LRUTxCache)(nil)
 type LRUTxCache struct {
 	mtx      sync.Mutex
 	size     int
+	ttl      time.Duration
 	cacheMap map[types.TxKey]*list.Element
 	list     *list.List
+	now      func() time.Time
+}
+
+// cacheEntry is the value of the elements of an LRUTxCache's list.
+type cacheEntry struct {
+	key   types.TxKey
+	added time.Time
 }
 
 func NewLRUTxCache(cacheSize int) *LRUTxCache {
+	return NewLRUTxCacheWithTTL(cacheSize, 0)
+}
+
+// NewLRUTxCacheWithTTL returns an LRUTxCache whose entries expire ttl after
+// they were added. If ttl is zero, entries only leave the cache when they are
+// evicted or removed.
+func NewLRUTxCacheWithTTL(cacheSize int, ttl time.Duration) *LRUTxCache {
 	return &LRUTxCache{
 		size:     cacheSize,
+		ttl:      ttl,
 		cacheMap: make(map[types.TxKey]*list.Element, cacheSize),
 		list:     list.New(),
+		now:      time.Now,
 	}
 }
 
@@ -62,28 +82,44 @@ func (c *LRUTxCache) Push(tx types.Tx) bool {
 	defer c.mtx.Unlock()
 
 	key := tx.Key()
+	now := c.now()
 
 	moved, ok := c.cacheMap[key]
 	if ok {
 		c.list.MoveToBack(moved)
+
+		// an expired entry is added again
+		entry := moved.Value.(*cacheEntry)
+		if c.ttl > 0 && now.Sub(entry.added) >= c.ttl {
+			entry.added = now
+			return true
+		}
 		return false
 	}
 
 	if c.list.Len() >= c.size {
 		front := c.list.Front()
 		if front != nil {
-			frontKey := front.Value.(types.TxKey)
+			frontKey := front.Value.(*cacheEntry).key
 			delete(c.cacheMap, frontKey)
 			c.list.Remove(front)
 		}
 	}
 
-	e := c.list.PushBack(key)
+	e := c.list.PushBack(&cacheEntry{key: key, added: now})
 	c.cacheMap[key] = e
 
 	return true
 }
 
+// len returns the number of entries in the cache.
+func (c *LRUTxCache) len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.list.Len()
+}
+
 func (c *LRUTxCache) Remove(tx types.Tx) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
@@ -105,3 +141,164 @@ var _ TxCache = (*NopTxCache)(nil)
 func (NopTxCache) Reset()             {}
 func (NopTxCache) Push(types.Tx) bool { return true }
 func (NopTxCache) Remove(types.Tx)    {}
+
+// maxRejectionLogBytes is the length past which the log of a cached rejection
+// is truncated.
+const maxRejectionLogBytes = 256
+
+// rejectionCache maintains a thread-safe LRU cache of the responses of the
+// transactions permanently rejected by the application's CheckTx, i.e. with
+// one of the codes configured in RejectionCacheCodes. A nil rejectionCache is
+// disabled.
+type rejectionCache struct {
+	mtx      sync.Mutex
+	size     int
+	ttl      time.Duration
+	codes    map[uint32]struct{}
+	cacheMap map[types.TxKey]*list.Element
+	list     *list.List
+	now      func() time.Time
+
+	// logBytes is the size of the logs and codespaces of the entries.
+	logBytes int64
+}
+
+// rejectionEntry is the value of the elements of a rejectionCache's list.
+type rejectionEntry struct {
+	key   types.TxKey
+	res   abci.ResponseCheckTx
+	added time.Time
+}
+
+func (e *rejectionEntry) logBytes() int64 {
+	return int64(len(e.res.Log) + len(e.res.Codespace))
+}
+
+// newRejectionCache returns a rejection cache of the size in cfg, or nil if the
+// cache is disabled.
+func newRejectionCache(cfg *config.MempoolConfig) *rejectionCache {
+	if cfg.RejectionCacheSize == 0 || cfg.KeepInvalidTxsInCache {
+		return nil
+	}
+
+	codes := make(map[uint32]struct{}, len(cfg.RejectionCacheCodes))
+	for _, code := range cfg.RejectionCacheCodes {
+		codes[code] = struct{}{}
+	}
+
+	return &rejectionCache{
+		size:     cfg.RejectionCacheSize,
+		ttl:      cfg.CacheTTL,
+		codes:    codes,
+		cacheMap: make(map[types.TxKey]*list.Element, cfg.RejectionCacheSize),
+		list:     list.New(),
+		now:      time.Now,
+	}
+}
+
+// get returns the response CheckTx rejected the transaction with, if the
+// rejection is cached and has not expired.
+func (c *rejectionCache) get(key types.TxKey) (*abci.ResponseCheckTx, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	e, ok := c.cacheMap[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := e.Value.(*rejectionEntry)
+	if c.ttl > 0 && c.now().Sub(entry.added) >= c.ttl {
+		c.remove(e)
+		return nil, false
+	}
+
+	c.list.MoveToBack(e)
+	res := entry.res
+	return &res, true
+}
+
+// push records that CheckTx rejected the transaction with res, if its code is
+// one of the cache's. Only the fields describing the rejection are kept, with
+// the log truncated to maxRejectionLogBytes.
+func (c *rejectionCache) push(key types.TxKey, res *abci.ResponseCheckTx) {
+	if c == nil {
+		return
+	}
+	if _, ok := c.codes[res.Code]; !ok {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	log := res.Log
+	if len(log) > maxRejectionLogBytes {
+		log = log[:maxRejectionLogBytes]
+	}
+	entry := &rejectionEntry{
+		key: key,
+		res: abci.ResponseCheckTx{
+			Code:      res.Code,
+			Codespace: res.Codespace,
+			Log:       log,
+		},
+		added: c.now(),
+	}
+
+	c.logBytes += entry.logBytes()
+
+	if e, ok := c.cacheMap[key]; ok {
+		c.logBytes -= e.Value.(*rejectionEntry).logBytes()
+		e.Value = entry
+		c.list.MoveToBack(e)
+		return
+	}
+
+	if c.list.Len() >= c.size {
+		if front := c.list.Front(); front != nil {
+			c.remove(front)
+		}
+	}
+
+	c.cacheMap[key] = c.list.PushBack(entry)
+}
+
+// remove drops the entry of e. The caller must hold the cache's lock.
+func (c *rejectionCache) remove(e *list.Element) {
+	entry := e.Value.(*rejectionEntry)
+	delete(c.cacheMap, entry.key)
+	c.list.Remove(e)
+	c.logBytes -= entry.logBytes()
+}
+
+// sizeBytes returns an estimate of the memory held by the cache, see
+// rejectionEntryBytes.
+func (c *rejectionCache) sizeBytes() int64 {
+	if c == nil {
+		return 0
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return int64(c.list.Len())*rejectionEntryBytes + c.logBytes
+}
+
+// reset forgets all rejections.
+func (c *rejectionCache) reset() {
+	if c == nil {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.cacheMap = make(map[types.TxKey]*list.Element, c.size)
+	c.list.Init()
+	c.logBytes = 0
+}
@@ -2,9 +2,15 @@ package mempool
 
 import (
 	"crypto/rand"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/types"
 )
 
 func TestCacheRemove(t *testing.T) {
@@ -33,3 +39,44 @@ func TestCacheRemove(t *testing.T) {
 		require.Equal(t, numTxs-(i+1), cache.list.Len())
 	}
 }
+
+func TestCacheTTL(t *testing.T) {
+	now := time.Now()
+	cache := NewLRUTxCacheWithTTL(100, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	tx := []byte("tx")
+	require.True(t, cache.Push(tx))
+	require.False(t, cache.Push(tx))
+
+	// a hit does not extend the lifetime of the entry
+	now = now.Add(30 * time.Second)
+	require.False(t, cache.Push(tx))
+
+	now = now.Add(30 * time.Second)
+	require.True(t, cache.Push(tx))
+	require.False(t, cache.Push(tx))
+	require.Equal(t, 1, cache.list.Len())
+}
+
+func TestRejectionCache(t *testing.T) {
+	cfg := config.TestMempoolConfig()
+	cfg.RejectionCacheSize = 10
+	cfg.RejectionCacheCodes = []uint32{1}
+	cache := newRejectionCache(cfg)
+
+	// only the rejections with one of the configured codes are cached
+	transient := types.Tx("transient").Key()
+	cache.push(transient, &abci.ResponseCheckTx{Code: 2})
+	_, ok := cache.get(transient)
+	require.False(t, ok)
+
+	// with their log truncated
+	permanent := types.Tx("permanent").Key()
+	cache.push(permanent, &abci.ResponseCheckTx{Code: 1, Codespace: "sdk", Log: strings.Repeat("x", 10*maxRejectionLogBytes)})
+	res, ok := cache.get(permanent)
+	require.True(t, ok)
+	require.Equal(t, uint32(1), res.Code)
+	require.Equal(t, "sdk", res.Codespace)
+	require.Len(t, res.Log, maxRejectionLogBytes)
+}
@@ -0,0 +1,82 @@
+package mempool
+
+import (
+	"bytes"
+
+	"github.com/tendermint/tendermint/config"
+)
+
+// evictionPolicy defines which resident transactions may be evicted, and in
+// which order, to make room for an incoming transaction when the mempool is
+// full.
+type evictionPolicy struct {
+	// canEvict reports whether resident may be evicted in favor of incoming.
+	canEvict func(resident, incoming *WrappedTx) bool
+
+	// less reports whether a should be evicted before b. It must be a total
+	// order so that the same transactions are always evicted.
+	less func(a, b *WrappedTx) bool
+}
+
+// evictionPolicies maps the policies that can be selected via the mempool's
+// eviction-policy config to their implementation.
+var evictionPolicies = map[string]evictionPolicy{
+	// evict the lowest priority transactions first, but only those of lower
+	// priority than the incoming transaction
+	config.EvictionPolicyPriority: {
+		canEvict: func(resident, incoming *WrappedTx) bool {
+			return resident.priority < incoming.priority
+		},
+		less: func(a, b *WrappedTx) bool {
+			if a.priority != b.priority {
+				return a.priority < b.priority
+			}
+			return receivedBefore(a, b)
+		},
+	},
+
+	// evict the transactions validated at the oldest height first, but only
+	// those validated at an earlier height than the incoming transaction
+	config.EvictionPolicyOldestHeight: {
+		canEvict: func(resident, incoming *WrappedTx) bool {
+			return resident.height < incoming.height
+		},
+		less: func(a, b *WrappedTx) bool {
+			if a.height != b.height {
+				return a.height < b.height
+			}
+			if a.priority != b.priority {
+				return a.priority < b.priority
+			}
+			return receivedBefore(a, b)
+		},
+	},
+
+	// evict the earliest received transactions first, regardless of their
+	// priority
+	config.EvictionPolicyFIFO: {
+		canEvict: func(resident, incoming *WrappedTx) bool {
+			return true
+		},
+		less: receivedBefore,
+	},
+}
+
+// lookupEvictionPolicy returns the implementation of the named policy, if any.
+// An empty name selects the priority policy.
+func lookupEvictionPolicy(name string) (evictionPolicy, bool) {
+	if name == "" {
+		name = config.EvictionPolicyPriority
+	}
+	policy, ok := evictionPolicies[name]
+	return policy, ok
+}
+
+// receivedBefore orders transactions by the time they were received, breaking
+// ties by hash.
+func receivedBefore(a, b *WrappedTx) bool {
+	if !a.timestamp.Equal(b.timestamp) {
+		return a.timestamp.Before(b.timestamp)
+	}
+	return bytes.Compare(a.hash[:], b.hash[:]) < 0
+}
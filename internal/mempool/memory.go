@@ -0,0 +1,100 @@
+package mempool
+
+import (
+	"container/list"
+	"sync/atomic"
+	"unsafe"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/internal/libs/clist"
+)
+
+// mapEntryBytes estimates the memory held by an entry of a map keyed by a
+// TxKey or a string, with a pointer value, including the map's bucket
+// overhead.
+const mapEntryBytes = 64
+
+// The following estimate the memory the mempool holds besides the bytes of its
+// transactions. They are lower bounds: memory referenced by a transaction's
+// metadata, e.g. its sender, and the slack of grown maps and slices are not
+// counted.
+var (
+	// txOverheadBytes is the memory held for each transaction in the mempool:
+	// its WrappedTx, its gossip index element, its entries in the tx store's
+	// maps, and its entries in the priority, height and timestamp indexes.
+	txOverheadBytes = int64(unsafe.Sizeof(WrappedTx{})+unsafe.Sizeof(clist.CElement{})) +
+		2*mapEntryBytes + 3*int64(unsafe.Sizeof(&WrappedTx{}))
+
+	// pendingTxOverheadBytes is the memory held for each pending transaction:
+	// its WrappedTx and the CheckTx response it is pending with.
+	pendingTxOverheadBytes = int64(unsafe.Sizeof(WrappedTx{}) + unsafe.Sizeof(TxWithResponse{}) +
+		unsafe.Sizeof(abci.ResponseCheckTxV2{}) + unsafe.Sizeof(abci.ResponseCheckTx{}))
+
+	// cacheEntryBytes is the memory held for each entry of the cache of seen
+	// transactions.
+	cacheEntryBytes = int64(unsafe.Sizeof(cacheEntry{})+unsafe.Sizeof(list.Element{})) + mapEntryBytes
+
+	// rejectionEntryBytes is the memory held for each entry of the rejection
+	// cache, besides its log and codespace.
+	rejectionEntryBytes = int64(unsafe.Sizeof(rejectionEntry{})+unsafe.Sizeof(list.Element{})) + mapEntryBytes
+)
+
+// memoryUsage is an estimate of the memory held by the mempool, by component.
+type memoryUsage struct {
+	// txs is the size of the transactions, including the pending ones.
+	txs int64
+	// overhead is the memory held for the transactions besides their bytes,
+	// see txOverheadBytes and pendingTxOverheadBytes.
+	overhead int64
+	// cache is the memory held by the cache of seen transactions.
+	cache int64
+	// rejectionCache is the memory held by the rejection cache.
+	rejectionCache int64
+}
+
+func (u memoryUsage) total() int64 {
+	return u.txs + u.overhead + u.cache + u.rejectionCache
+}
+
+// memoryUsage returns an estimate of the memory held by the mempool. It is
+// thread-safe.
+func (txmp *TxMempool) memoryUsage() memoryUsage {
+	usage := memoryUsage{
+		txs: txmp.SizeBytes() + atomic.LoadInt64(&txmp.pendingSizeBytes),
+		overhead: int64(txmp.NumTxsNotPending())*txOverheadBytes +
+			int64(txmp.PendingSize())*pendingTxOverheadBytes,
+		rejectionCache: txmp.rejections.sizeBytes(),
+	}
+	if cache, ok := txmp.cache.(*LRUTxCache); ok {
+		usage.cache = int64(cache.len()) * cacheEntryBytes
+	}
+	return usage
+}
+
+// updateMemoryMetrics sets the metrics of the memory held by the mempool.
+func (txmp *TxMempool) updateMemoryMetrics() {
+	usage := txmp.memoryUsage()
+	txmp.metrics.MemoryBytes.With("component", "txs").Set(float64(usage.txs))
+	txmp.metrics.MemoryBytes.With("component", "overhead").Set(float64(usage.overhead))
+	txmp.metrics.MemoryBytes.With("component", "cache").Set(float64(usage.cache))
+	txmp.metrics.MemoryBytes.With("component", "rejection_cache").Set(float64(usage.rejectionCache))
+}
+
+// evictionBounds returns the size of the mempool, the limit it must stay
+// within for an incoming transaction to be admitted and the overhead to count
+// for each transaction, to select the transactions to evict for it: the size
+// of its transactions, MaxTxsBytes and no overhead, or, if MaxMemoryBytes
+// leaves less room, the estimated memory the mempool holds, MaxMemoryBytes and
+// txOverheadBytes.
+func (txmp *TxMempool) evictionBounds() (size, limit, txOverhead int64) {
+	size, limit = txmp.SizeBytes(), txmp.config.MaxTxsBytes
+	if txmp.config.MaxMemoryBytes <= 0 {
+		return size, limit, 0
+	}
+
+	memory := txmp.memoryUsage().total()
+	if txmp.config.MaxMemoryBytes-memory-txOverheadBytes < limit-size {
+		return memory, txmp.config.MaxMemoryBytes, txOverheadBytes
+	}
+	return size, limit, 0
+}
@@ -0,0 +1,99 @@
+package mempool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abciclient "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestTxMempool_MemoryUsage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	require.NoError(t, client.Start(ctx))
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 100)
+	txmp.config.RejectionCacheSize = 10
+	txmp.config.RejectionCacheCodes = []uint32{101}
+	txmp.rejections = newRejectionCache(txmp.config)
+
+	_ = checkTxs(ctx, t, txmp, 20, 0)
+	require.NoError(t, txmp.CheckTx(ctx, types.Tx("bad tx"), nil, TxInfo{}))
+
+	usage := txmp.memoryUsage()
+	require.Equal(t, txmp.SizeBytes(), usage.txs)
+	require.Equal(t, 20*txOverheadBytes, usage.overhead)
+	// the rejected tx is removed from the cache of seen txs
+	require.Equal(t, 20*cacheEntryBytes, usage.cache)
+	require.Equal(t, rejectionEntryBytes, usage.rejectionCache)
+	require.Equal(t, usage.txs+usage.overhead+usage.cache+usage.rejectionCache, usage.total())
+}
+
+func TestRejectionCacheSizeBytes(t *testing.T) {
+	cfg := config.TestMempoolConfig()
+	cfg.RejectionCacheSize = 2
+	cfg.RejectionCacheCodes = []uint32{1}
+	cache := newRejectionCache(cfg)
+	require.Zero(t, cache.sizeBytes())
+
+	res := &abci.ResponseCheckTx{Code: 1, Codespace: "sdk", Log: strings.Repeat("x", 10*maxRejectionLogBytes)}
+	entryBytes := rejectionEntryBytes + int64(len("sdk")+maxRejectionLogBytes)
+	for i := 0; i < 3; i++ {
+		cache.push(types.Tx(fmt.Sprintf("tx-%d", i)).Key(), res)
+	}
+	require.Equal(t, 2*entryBytes, cache.sizeBytes())
+
+	// a rejection pushed again replaces the previous one
+	cache.push(types.Tx("tx-2").Key(), &abci.ResponseCheckTx{Code: 1})
+	require.Equal(t, entryBytes+rejectionEntryBytes, cache.sizeBytes())
+
+	cache.reset()
+	require.Zero(t, cache.sizeBytes())
+}
+
+func TestTxMempool_MaxMemoryBytes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	require.NoError(t, client.Start(ctx))
+	t.Cleanup(client.Wait)
+
+	newTx := func(i int, priority int64) types.Tx {
+		return types.Tx(fmt.Sprintf("sender-%03d=key=%04d", i, priority))
+	}
+	txBytes := int64(len(newTx(0, 0)))
+
+	// the memory limit, not max-txs-bytes, leaves room for 10 txs
+	txmp := setup(t, client, 0)
+	txmp.config.MaxMemoryBytes = 10 * (txBytes + txOverheadBytes)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, txmp.CheckTx(ctx, newTx(i, int64(1000+i)), nil, TxInfo{}))
+	}
+	require.Equal(t, 10, txmp.Size())
+	require.Less(t, txmp.SizeBytes()+txBytes, txmp.config.MaxTxsBytes)
+
+	// a tx with a lower priority than all others is rejected
+	require.NoError(t, txmp.CheckTx(ctx, newTx(10, 1), nil, TxInfo{}))
+	require.Equal(t, 10, txmp.Size())
+	require.False(t, txmp.HasTx(newTx(10, 1).Key()))
+
+	// a tx with a higher priority evicts the lowest priority one
+	require.NoError(t, txmp.CheckTx(ctx, newTx(11, 9999), nil, TxInfo{}))
+	require.Equal(t, 10, txmp.Size())
+	require.True(t, txmp.HasTx(newTx(11, 9999).Key()))
+	require.False(t, txmp.HasTx(newTx(0, 1000).Key()))
+	require.LessOrEqual(t, txmp.memoryUsage().total(), txmp.config.MaxMemoryBytes)
+}
@@ -1,7 +1,6 @@
 package mempool
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,6 +9,10 @@ import (
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
 	abciclient "github.com/tendermint/tendermint/abci/client"
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/config"
@@ -20,6 +23,25 @@ import (
 
 var _ Mempool = (*TxMempool)(nil)
 
+// tracerName is the name of the tracer creating the mempool's spans.
+const tracerName = "tm-mempool"
+
+const (
+	// recheckRetryMinBackoff and recheckRetryMaxBackoff bound the delay between
+	// two attempts at rechecking the stale transactions.
+	recheckRetryMinBackoff = 10 * time.Millisecond
+	recheckRetryMaxBackoff = 5 * time.Second
+
+	// recheckRetryAttempts is the number of failed attempts at rechecking the
+	// stale transactions, within recheck-retry-timeout, after which the
+	// mempool is marked degraded.
+	recheckRetryAttempts = 5
+
+	// txEventQueueSize is the number of transaction events queued for the
+	// event publisher, past which they are dropped.
+	txEventQueueSize = 1024
+)
+
 // TxMempoolOption sets an optional parameter on the TxMempool.
 type TxMempoolOption func(*TxMempool)
 
@@ -30,6 +52,7 @@ type TxMempoolOption func(*TxMempool)
 type TxMempool struct {
 	logger       log.Logger
 	metrics      *Metrics
+	tracer       trace.Tracer
 	config       *config.MempoolConfig
 	proxyAppConn abciclient.Client
 
@@ -50,27 +73,38 @@ type TxMempool struct {
 	// reduces pressure on the proxyApp.
 	cache TxCache
 
+	// rejections defines a fixed-size cache of the transactions recently
+	// rejected by CheckTx, used to answer resubmissions without executing
+	// CheckTx again. It is nil if disabled.
+	rejections *rejectionCache
+
 	// txStore defines the main storage of valid transactions. Indexes are built
 	// on top of this store.
 	txStore *TxStore
 
 	// gossipIndex defines the gossiping index of valid transactions via a
-	// thread-safe linked-list. We also use the gossip index as a cursor for
+	// thread-safe linked-list. A snapshot of the gossip index is taken when
 	// rechecking transactions already in the mempool.
 	gossipIndex *clist.CList
 
-	// recheckCursor and recheckEnd are used as cursors based on the gossip index
-	// to recheck transactions that are already in the mempool. Iteration is not
-	// thread-safe and transaction may be mutated in serial order.
-	//
-	// XXX/TODO: It might be somewhat of a codesmell to use the gossip index for
-	// iterator and cursor management when rechecking transactions. If the gossip
-	// index changes or is removed in a future refactor, this will have to be
-	// refactored. Instead, we should consider just keeping a slice of a snapshot
-	// of the mempool's current transactions during Update and an integer cursor
-	// into that slice. This, however, requires additional O(n) space complexity.
-	recheckCursor *clist.CElement // next expected response
-	recheckEnd    *clist.CElement // re-checking stops here
+	// degraded is set (1) when the re-CheckTx of the mempool's transactions
+	// after a block could not be completed because the app connection failed,
+	// and could still not be once recheck-retry-timeout elapsed. Transactions
+	// that were not rechecked are marked stale and are never reaped. It is
+	// reset once all stale transactions were rechecked.
+	degraded int32
+
+	// lastBreakerState is the state of the CheckTx circuit breaker after the
+	// latest CheckTx call, see setBreakerState.
+	lastBreakerState int32
+
+	// recovering is true while a goroutine is retrying the recheck of stale
+	// transactions.
+	recovering bool
+
+	// lifetime is done once the mempool's reactor stopped. It bounds the
+	// background routines which outlive the call that started them.
+	lifetime context.Context
 
 	// priorityIndex defines the priority index of valid transactions via a
 	// thread-safe priority queue.
@@ -101,6 +135,30 @@ type TxMempool struct {
 	mtxFailedCheckTxCounts sync.RWMutex
 
 	peerManager PeerEvictor
+
+	// rateLimiter enforces the per-peer submission quotas. It is nil if no
+	// quota is configured.
+	rateLimiter *peerRateLimiter
+
+	// eventPublisher publishes the transaction events, if enabled in the
+	// config. It may be nil.
+	eventPublisher types.MempoolEventPublisher
+
+	// txEvents queues the transaction events for the event publisher, which
+	// may block, so that they are never published under the lock.
+	txEvents chan txEvent
+
+	// breaker sheds load while the application's CheckTx is slow. It is nil
+	// if the breaker is disabled.
+	breaker *circuitBreaker
+
+	// evictionPolicy selects the transactions to evict when the mempool is
+	// full.
+	evictionPolicy evictionPolicy
+
+	// wal persists the mempool's transactions across restarts. It is nil
+	// unless the WAL is enabled and InitWAL was called.
+	wal *mempoolWAL
 }
 
 func NewTxMempool(
@@ -118,6 +176,7 @@ func NewTxMempool(
 		height:        -1,
 		cache:         NopTxCache{},
 		metrics:       NopMetrics(),
+		tracer:        trace.NewNoopTracerProvider().Tracer(tracerName),
 		txStore:       NewTxStore(),
 		gossipIndex:   clist.New(),
 		priorityIndex: NewTxPriorityQueue(),
@@ -130,10 +189,20 @@ func NewTxMempool(
 		pendingTxs:          NewPendingTxs(cfg),
 		failedCheckTxCounts: map[types.NodeID]uint64{},
 		peerManager:         peerManager,
+		rateLimiter:         newPeerRateLimiter(cfg),
+		evictionPolicy:      evictionPolicies[config.EvictionPolicyPriority],
+		breaker:             newCircuitBreaker(cfg),
+		rejections:          newRejectionCache(cfg),
+		txEvents:            make(chan txEvent, txEventQueueSize),
+		lifetime:            context.Background(),
+	}
+
+	if policy, ok := lookupEvictionPolicy(cfg.EvictionPolicy); ok {
+		txmp.evictionPolicy = policy
 	}
 
 	if cfg.CacheSize > 0 {
-		txmp.cache = NewLRUTxCache(cfg.CacheSize)
+		txmp.cache = NewLRUTxCacheWithTTL(cfg.CacheSize, cfg.CacheTTL)
 	}
 
 	for _, opt := range options {
@@ -162,6 +231,22 @@ func WithMetrics(metrics *Metrics) TxMempoolOption {
 	return func(txmp *TxMempool) { txmp.metrics = metrics }
 }
 
+// WithEventPublisher sets the publisher of the mempool's transaction events.
+// The events are only published if enabled in the mempool config.
+func WithEventPublisher(p types.MempoolEventPublisher) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.eventPublisher = p }
+}
+
+// WithTracerProviderOptions traces CheckTx and Update with a tracer provider
+// built from opts. Tracing is disabled if opts is empty.
+func WithTracerProviderOptions(opts []sdktrace.TracerProviderOption) TxMempoolOption {
+	return func(txmp *TxMempool) {
+		if len(opts) > 0 {
+			txmp.tracer = sdktrace.NewTracerProvider(opts...).Tracer(tracerName)
+		}
+	}
+}
+
 func (txmp *TxMempool) TxStore() *TxStore {
 	return txmp.txStore
 }
@@ -201,6 +286,27 @@ func (txmp *TxMempool) TotalTxsBytesSize() int64 {
 	return txmp.BytesNotPending() + int64(txmp.pendingTxs.sizeBytes)
 }
 
+// IsDegraded returns true if the mempool holds stale transactions, i.e.
+// transactions whose re-CheckTx after the latest block could not be completed
+// because of app connection failures. It is thread-safe.
+func (txmp *TxMempool) IsDegraded() bool {
+	return atomic.LoadInt32(&txmp.degraded) == 1
+}
+
+// CheckTxBreakerState returns the state of the CheckTx circuit breaker after
+// the latest CheckTx call: "closed", "open" or "half-open". A disabled breaker
+// is always closed. It is thread-safe.
+func (txmp *TxMempool) CheckTxBreakerState() string {
+	return breakerState(atomic.LoadInt32(&txmp.lastBreakerState)).String()
+}
+
+// setBreakerState records the state of the CheckTx circuit breaker, for
+// CheckTxBreakerState and the CheckTxBreakerState metric.
+func (txmp *TxMempool) setBreakerState(state breakerState) {
+	atomic.StoreInt32(&txmp.lastBreakerState, int32(state))
+	txmp.metrics.CheckTxBreakerState.Set(float64(state))
+}
+
 // PendingSize returns the number of pending transactions in the mempool.
 func (txmp *TxMempool) PendingSize() int {
 	return txmp.pendingTxs.Size()
@@ -264,11 +370,18 @@ func (txmp *TxMempool) TxsAvailable() <-chan struct{} {
 //     configuration provided to the mempool.
 //   - The transaction fails Pre-Check (if it is defined).
 //   - The proxyAppConn fails, e.g. the buffer is full.
+//   - The sending peer exceeded its rate limit (if one is configured).
+//   - The application is overloaded (if the CheckTx circuit breaker is enabled).
 //
 // If the mempool is full, we still execute CheckTx and attempt to find a lower
 // priority transaction to evict. If such a transaction exists, we remove the
 // lower priority transaction and add the new one with higher priority.
 //
+// If the rejection cache is enabled and CheckTx permanently rejected the
+// transaction since the last block, cb is called with the cached response and
+// CheckTx is not executed again. The rejection still counts against the
+// sending peer.
+//
 // NOTE:
 // - The applications' CheckTx implementation may panic.
 // - The caller is not to explicitly require any locks for executing CheckTx.
@@ -277,7 +390,25 @@ func (txmp *TxMempool) CheckTx(
 	tx types.Tx,
 	cb func(*abci.ResponseCheckTx),
 	txInfo TxInfo,
-) error {
+) (err error) {
+	txHash := tx.Key()
+
+	ctx, span := txmp.tracer.Start(ctx, "mempool.CheckTx")
+	if span.IsRecording() {
+		span.SetAttributes(
+			attribute.String("tx", fmt.Sprintf("%X", txHash[:])),
+			attribute.Int("size", len(tx)),
+			attribute.String("peer", string(txInfo.SenderNodeID)),
+			attribute.Bool("private", txInfo.Private),
+		)
+	}
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+
 	txmp.mtx.RLock()
 	defer txmp.mtx.RUnlock()
 
@@ -298,17 +429,76 @@ func (txmp *TxMempool) CheckTx(
 		return err
 	}
 
-	txHash := tx.Key()
+	// If CheckTx rejected the transaction since the last block, answer with the
+	// cached rejection instead of executing CheckTx again.
+	if txmp.rejections != nil {
+		if res, ok := txmp.rejections.get(txHash); ok {
+			txmp.metrics.CacheHits.With("cache", "rejection").Add(1)
+			txmp.countFailedCheckTx(txInfo.SenderNodeID)
+			if cb != nil {
+				cb(res)
+			}
+			return nil
+		}
+		txmp.metrics.CacheMisses.With("cache", "rejection").Add(1)
+	}
 
 	// We add the transaction to the mempool's cache and if the
 	// transaction is already present in the cache, i.e. false is returned, then we
 	// check if we've seen this transaction and error if we have.
-	if !txmp.cache.Push(tx) {
+	// With a cache TTL, a transaction can outlive its cache entry, so the
+	// mempool itself is checked too. Without a cache, duplicates are left to
+	// CheckTx as before.
+	if !txmp.cache.Push(tx) || (txmp.config.CacheSize > 0 && txmp.txStore.GetTxByHash(txHash) != nil) {
+		txmp.metrics.CacheHits.With("cache", "seen").Add(1)
 		txmp.txStore.GetOrSetPeerByTxHash(txHash, txInfo.SenderID)
 		return types.ErrTxInCache
 	}
+	txmp.metrics.CacheMisses.With("cache", "seen").Add(1)
+
+	// Only charge the sending peer for transactions we actually execute
+	// CheckTx for.
+	if txInfo.RPC {
+		err = txmp.rateLimiter.allowRPC(len(tx))
+	} else {
+		err = txmp.rateLimiter.allow(txInfo.SenderNodeID, len(tx))
+	}
+	if err != nil {
+		txmp.cache.Remove(tx)
+		txmp.metrics.RateLimitedTxs.With("source", rateLimitSource(txInfo)).Add(1)
+		return err
+	}
 
-	res, err := txmp.proxyAppConn.CheckTx(ctx, &abci.RequestCheckTx{Tx: tx})
+	if err := txmp.breaker.allow(); err != nil {
+		txmp.cache.Remove(tx)
+		txmp.metrics.OverloadedTxs.Add(1)
+		return err
+	}
+
+	appCtx, appSpan := txmp.tracer.Start(ctx, "mempool.CheckTx.app")
+	start := time.Now()
+	res, err := txmp.proxyAppConn.CheckTx(appCtx, &abci.RequestCheckTx{Tx: tx})
+	if err == nil {
+		appSpan.SetAttributes(attribute.Int64("code", int64(res.Code)))
+	}
+	appSpan.End()
+	if txmp.breaker != nil {
+		var state breakerState
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			// the caller gave up, which says nothing about the application
+			state = txmp.breaker.abort()
+		} else {
+			state = txmp.breaker.done(time.Since(start), err)
+		}
+		txmp.setBreakerState(state)
+	}
+
+	// the application did not respond, e.g. because the caller canceled the
+	// call, so the transaction can be submitted again
+	if err != nil && res == nil {
+		txmp.cache.Remove(tx)
+		return err
+	}
 
 	// when a transaction is removed/expired/rejected, this should be called
 	// The expire tx handler unreserves the pending nonce
@@ -334,6 +524,8 @@ func (txmp *TxMempool) CheckTx(
 		evmNonce:      res.EVMNonce,
 		evmAddress:    res.EVMSenderAddress,
 		isEVM:         res.IsEVM,
+		private:       txInfo.Private,
+		senderID:      txInfo.SenderID,
 		removeHandler: removeHandler,
 	}
 
@@ -359,6 +551,12 @@ func (txmp *TxMempool) CheckTx(
 				return err
 			}
 		}
+
+		if res.Code == abci.CodeTypeOK && !txInfo.Private {
+			if err := txmp.wal.write(tx); err != nil {
+				txmp.logger.Error("failed to write tx to mempool wal", "err", err)
+			}
+		}
 	}
 
 	if cb != nil {
@@ -368,6 +566,20 @@ func (txmp *TxMempool) CheckTx(
 	return nil
 }
 
+// countFailedCheckTx charges a transaction rejected by CheckTx, including one
+// answered from the rejection cache, against the peer that sent it, and evicts
+// the peer once it exceeds the configured threshold.
+func (txmp *TxMempool) countFailedCheckTx(peerID types.NodeID) {
+	txmp.mtxFailedCheckTxCounts.Lock()
+	defer txmp.mtxFailedCheckTxCounts.Unlock()
+
+	txmp.failedCheckTxCounts[peerID]++
+	if txmp.config.CheckTxErrorBlacklistEnabled && txmp.failedCheckTxCounts[peerID] > uint64(txmp.config.CheckTxErrorThreshold) {
+		// evict peer
+		txmp.peerManager.Errored(peerID, errors.New("checkTx error exceeded threshold"))
+	}
+}
+
 func (txmp *TxMempool) isInMempool(tx types.Tx) bool {
 	existingTx := txmp.txStore.GetTxByHash(tx.Key())
 	return existingTx != nil && !existingTx.removed
@@ -379,7 +591,7 @@ func (txmp *TxMempool) RemoveTxByKey(txKey types.TxKey) error {
 
 	// remove the committed transaction from the transaction store and indexes
 	if wtx := txmp.txStore.GetTxByHash(txKey); wtx != nil {
-		txmp.removeTx(wtx, false, true, true)
+		txmp.removeTx(wtx, types.MempoolTxRemoved, false, true, true)
 		return nil
 	}
 
@@ -418,11 +630,196 @@ func (txmp *TxMempool) Flush() {
 	txmp.timestampIndex.Reset()
 
 	for _, wtx := range txmp.txStore.GetAllTxs() {
-		txmp.removeTx(wtx, false, false, true)
+		txmp.removeTx(wtx, types.MempoolTxFlushed, false, false, true)
 	}
 
 	atomic.SwapInt64(&txmp.sizeBytes, 0)
 	txmp.cache.Reset()
+	txmp.rejections.reset()
+}
+
+// ExportTxs returns the transactions currently in the mempool, including the
+// pending ones, in arrival order. Private transactions are never exported.
+func (txmp *TxMempool) ExportTxs() types.Txs {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+
+	return txmp.exportTxs()
+}
+
+// NOTE:
+// - The caller must have a read-lock when executing exportTxs.
+func (txmp *TxMempool) exportTxs() types.Txs {
+	txs := make(types.Txs, 0, txmp.Size())
+	for e := txmp.gossipIndex.Front(); e != nil; e = e.Next() {
+		if wtx := e.Value.(*WrappedTx); !wtx.private {
+			txs = append(txs, wtx.tx)
+		}
+	}
+
+	txmp.pendingTxs.mtx.RLock()
+	for _, ptx := range txmp.pendingTxs.txs {
+		if !ptx.tx.private {
+			txs = append(txs, ptx.tx.tx)
+		}
+	}
+	txmp.pendingTxs.mtx.RUnlock()
+
+	return txs
+}
+
+// ForEachExportedTx calls fn for each of the transactions ExportTxs returns, in
+// the same order, until fn returns false. The gossip index is iterated without
+// holding the mempool's lock, like the reactor does to broadcast transactions,
+// so fn may block, e.g. on a write to a slow client. Transactions added or
+// removed meanwhile may or may not be seen.
+func (txmp *TxMempool) ForEachExportedTx(fn func(types.Tx) bool) {
+	for e := txmp.gossipIndex.Front(); e != nil; e = e.Next() {
+		if wtx := e.Value.(*WrappedTx); !wtx.private && !fn(wtx.tx) {
+			return
+		}
+	}
+
+	txmp.pendingTxs.mtx.RLock()
+	pending := make([]*WrappedTx, 0, len(txmp.pendingTxs.txs))
+	for _, ptx := range txmp.pendingTxs.txs {
+		if !ptx.tx.private {
+			pending = append(pending, ptx.tx)
+		}
+	}
+	txmp.pendingTxs.mtx.RUnlock()
+
+	for _, wtx := range pending {
+		if !fn(wtx.tx) {
+			return
+		}
+	}
+}
+
+// ImportTxs executes CheckTx for each of txs as if it was submitted locally,
+// e.g. to restore the transactions exported from another node, and returns
+// the number of transactions the application accepted. Transactions that are
+// rejected or already known are skipped. It only returns an error if ctx is
+// canceled.
+func (txmp *TxMempool) ImportTxs(ctx context.Context, txs types.Txs) (int, error) {
+	var accepted int
+	for _, tx := range txs {
+		err := txmp.CheckTx(ctx, tx, func(res *abci.ResponseCheckTx) {
+			if res.Code == abci.CodeTypeOK {
+				accepted++
+			}
+		}, TxInfo{})
+		if err != nil {
+			if ctx.Err() != nil {
+				return accepted, ctx.Err()
+			}
+			txmp.logger.Debug("dropping imported tx",
+				"tx", fmt.Sprintf("%X", tx.Hash()),
+				"err", err)
+		}
+	}
+	return accepted, nil
+}
+
+// UpdateConfig applies update to a copy of the mempool's config and, if the
+// result is valid, swaps it in atomically. Size limits, TTLs, rate limits, the
+// eviction policy and the CheckTx circuit breaker take effect immediately.
+// Changing a setting that is only read at startup, see startupSetting, is
+// rejected.
+//
+// Changing the rate limits or the circuit breaker settings resets their
+// state. Transactions already in the mempool are not evicted if the new limits
+// are lower.
+func (txmp *TxMempool) UpdateConfig(update func(*config.MempoolConfig)) error {
+	txmp.Lock()
+	defer txmp.Unlock()
+
+	old := txmp.config
+	cfg := *old
+	update(&cfg)
+	if err := cfg.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid mempool config: %w", err)
+	}
+	if name := startupSetting(old, &cfg); name != "" {
+		return fmt.Errorf("invalid mempool config: %s can't be changed without restarting the node", name)
+	}
+
+	txmp.config = &cfg
+
+	txmp.pendingTxs.mtx.Lock()
+	txmp.pendingTxs.config = &cfg
+	txmp.pendingTxs.mtx.Unlock()
+
+	if cfg.PeerTxRateLimit != old.PeerTxRateLimit || cfg.PeerBytesRateLimit != old.PeerBytesRateLimit ||
+		cfg.RPCTxRateLimit != old.RPCTxRateLimit || cfg.RPCBytesRateLimit != old.RPCBytesRateLimit {
+		txmp.rateLimiter = newPeerRateLimiter(&cfg)
+	}
+	if cfg.CheckTxLatencyThreshold != old.CheckTxLatencyThreshold || cfg.CheckTxBreakerCooldown != old.CheckTxBreakerCooldown {
+		txmp.breaker = newCircuitBreaker(&cfg)
+		txmp.setBreakerState(breakerClosed)
+	}
+	if policy, ok := lookupEvictionPolicy(cfg.EvictionPolicy); ok {
+		txmp.evictionPolicy = policy
+	}
+
+	txmp.logger.Info("updated mempool config",
+		"size", cfg.Size,
+		"max_txs_bytes", cfg.MaxTxsBytes,
+		"peer_tx_rate_limit", cfg.PeerTxRateLimit,
+		"peer_bytes_rate_limit", cfg.PeerBytesRateLimit,
+		"rpc_tx_rate_limit", cfg.RPCTxRateLimit,
+		"rpc_bytes_rate_limit", cfg.RPCBytesRateLimit,
+		"eviction_policy", cfg.EvictionPolicy)
+	return nil
+}
+
+// startupSetting returns the name of the first setting that differs between a
+// and b and is only read when the mempool and its reactor are created, or ""
+// if there is none. In particular, max-tx-bytes sizes the p2p channel's
+// receive capacity.
+func startupSetting(a, b *config.MempoolConfig) string {
+	switch {
+	case a.RootDir != b.RootDir:
+		return "home"
+	case a.Broadcast != b.Broadcast:
+		return "broadcast"
+	case a.CacheSize != b.CacheSize:
+		return "cache-size"
+	case a.CacheTTL != b.CacheTTL:
+		return "cache-ttl"
+	case a.RejectionCacheSize != b.RejectionCacheSize:
+		return "rejection-cache-size"
+	case !equalCodes(a.RejectionCacheCodes, b.RejectionCacheCodes):
+		return "rejection-cache-codes"
+	case a.MaxTxBytes != b.MaxTxBytes:
+		return "max-tx-bytes"
+	case a.MaxBatchBytes != b.MaxBatchBytes:
+		return "max-batch-bytes"
+	case a.WalPath != b.WalPath:
+		return "wal-dir"
+	}
+	return ""
+}
+
+func equalCodes(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// removePeer drops the rate limiting state of a disconnected peer, once its
+// quotas refilled.
+func (txmp *TxMempool) removePeer(peerID types.NodeID) {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+
+	txmp.rateLimiter.removePeer(peerID)
 }
 
 // ReapMaxBytesMaxGas returns a list of transactions within the provided size
@@ -446,6 +843,11 @@ func (txmp *TxMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
 		return txs
 	}
 	txmp.priorityIndex.ForEachTx(func(wtx *WrappedTx) bool {
+		if wtx.stale {
+			// never reap transactions which could not be rechecked
+			return true
+		}
+
 		size := types.ComputeProtoSizeForTxs([]types.Tx{wtx.tx})
 
 		if maxBytes > -1 && totalSize+size > maxBytes {
@@ -467,7 +869,9 @@ func (txmp *TxMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
 }
 
 // ReapMaxTxs returns a list of transactions within the provided number of
-// transactions bound. Transaction are retrieved in priority order.
+// transactions bound. Transaction are retrieved in priority order. It is
+// used to expose the mempool's content, so private transactions are never
+// returned.
 //
 // NOTE:
 //   - Transactions returned are not removed from the mempool transaction
@@ -476,21 +880,42 @@ func (txmp *TxMempool) ReapMaxTxs(max int) types.Txs {
 	txmp.mtx.Lock()
 	defer txmp.mtx.Unlock()
 
-	wTxs := txmp.priorityIndex.PeekTxs(max)
-	txs := make([]types.Tx, 0, len(wTxs))
-	for _, wtx := range wTxs {
-		txs = append(txs, wtx.tx)
-	}
+	var txs []types.Tx
+	txmp.priorityIndex.ForEachTx(func(wtx *WrappedTx) bool {
+		if max >= 0 && len(txs) >= max {
+			return false
+		}
+		// never reap transactions which could not be rechecked, nor private ones
+		if !wtx.stale && !wtx.private {
+			txs = append(txs, wtx.tx)
+		}
+		return true
+	})
 	if len(txs) < max {
 		// retrieve more from pending txs
 		pending := txmp.pendingTxs.Peek(max - len(txs))
 		for _, ptx := range pending {
-			txs = append(txs, ptx.tx.tx)
+			if !ptx.tx.private {
+				txs = append(txs, ptx.tx.tx)
+			}
 		}
 	}
 	return txs
 }
 
+// ForEachTx calls fn for each of the transactions ReapMaxTxs(max) returns, in
+// the same order, until fn returns false. Priority order can only be taken
+// under the lock, so only references to the transactions are collected under
+// it, and fn is called without holding it, so it may block, e.g. on a write to
+// a slow client.
+func (txmp *TxMempool) ForEachTx(max int, fn func(types.Tx) bool) {
+	for _, tx := range txmp.ReapMaxTxs(max) {
+		if !fn(tx) {
+			return
+		}
+	}
+}
+
 // Update iterates over all the transactions provided by the block producer,
 // removes them from the cache (if applicable), and removes
 // the transactions from the main transaction store and associated indexes.
@@ -509,9 +934,18 @@ func (txmp *TxMempool) Update(
 	newPostFn PostCheckFunc,
 	recheck bool,
 ) error {
+	ctx, span := txmp.tracer.Start(ctx, "mempool.Update", trace.WithAttributes(
+		attribute.Int64("height", blockHeight),
+		attribute.Int("txs", len(blockTxs)),
+	))
+	defer span.End()
+
 	txmp.height = blockHeight
 	txmp.notifiedTxsAvailable = false
 
+	// the rejections were based on the application state before the block
+	txmp.rejections.reset()
+
 	if newPreFn != nil {
 		txmp.preCheck = newPreFn
 	}
@@ -530,7 +964,7 @@ func (txmp *TxMempool) Update(
 
 		// remove the committed transaction from the transaction store and indexes
 		if wtx := txmp.txStore.GetTxByHash(tx.Key()); wtx != nil {
-			txmp.removeTx(wtx, false, false, true)
+			txmp.removeTx(wtx, types.MempoolTxCommitted, false, false, true)
 		}
 		if execTxResult[i].EvmTxInfo != nil {
 			// remove any tx that has the same nonce (because the committed tx
@@ -539,7 +973,7 @@ func (txmp *TxMempool) Update(
 				evmAddress: execTxResult[i].EvmTxInfo.SenderAddress,
 				evmNonce:   execTxResult[i].EvmTxInfo.Nonce,
 			}); wtx != nil {
-				txmp.removeTx(wtx, false, false, true)
+				txmp.removeTx(wtx, types.MempoolTxCommitted, false, false, true)
 			}
 		}
 	}
@@ -563,8 +997,11 @@ func (txmp *TxMempool) Update(
 		}
 	}
 
+	txmp.compactWAL()
+
 	txmp.metrics.Size.Set(float64(txmp.NumTxsNotPending()))
 	txmp.metrics.TotalTxsSizeBytes.Set(float64(txmp.TotalTxsBytesSize()))
+	txmp.updateMemoryMetrics()
 	txmp.metrics.PendingSize.Set(float64(txmp.PendingSize()))
 	return nil
 }
@@ -610,13 +1047,8 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, res *abci.ResponseCheck
 
 		wtx.removeHandler(!txmp.config.KeepInvalidTxsInCache)
 		if res.Code != abci.CodeTypeOK {
-			txmp.mtxFailedCheckTxCounts.Lock()
-			defer txmp.mtxFailedCheckTxCounts.Unlock()
-			txmp.failedCheckTxCounts[txInfo.SenderNodeID]++
-			if txmp.config.CheckTxErrorBlacklistEnabled && txmp.failedCheckTxCounts[txInfo.SenderNodeID] > uint64(txmp.config.CheckTxErrorThreshold) {
-				// evict peer
-				txmp.peerManager.Errored(txInfo.SenderNodeID, errors.New("checkTx error exceeded threshold"))
-			}
+			txmp.rejections.push(wtx.hash, res)
+			txmp.countFailedCheckTx(txInfo.SenderNodeID)
 		}
 		return err
 	}
@@ -637,11 +1069,14 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, res *abci.ResponseCheck
 	}
 
 	if err := txmp.canAddTx(wtx); err != nil {
+		wtx.priority = priority
+		size, limit, txOverhead := txmp.evictionBounds()
 		evictTxs := txmp.priorityIndex.GetEvictableTxs(
-			priority,
-			int64(wtx.Size()),
-			txmp.SizeBytes(),
-			txmp.config.MaxTxsBytes,
+			txmp.evictionPolicy,
+			wtx,
+			size,
+			limit,
+			txOverhead,
 		)
 		if len(evictTxs) == 0 {
 			// No room for the new incoming transaction so we just remove it from
@@ -662,7 +1097,7 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, res *abci.ResponseCheck
 		// - The transaction, toEvict, can be removed while a concurrent
 		//   reCheckTx callback is being executed for the same transaction.
 		for _, toEvict := range evictTxs {
-			txmp.removeTx(toEvict, true, true, true)
+			txmp.removeTx(toEvict, types.MempoolTxEvicted, true, true, true)
 			txmp.logger.Debug(
 				"evicted existing good transaction; mempool full",
 				"old_tx", fmt.Sprintf("%X", toEvict.tx.Hash()),
@@ -699,108 +1134,116 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, res *abci.ResponseCheck
 	return nil
 }
 
-// handleRecheckResult handles the responses from ABCI CheckTx calls issued
-// during the recheck phase of a block Update.  It removes any transactions
-// invalidated by the application.
-//
-// The caller must hold a mempool write-lock (via Lock()) and when
-// executing Update(), if the mempool is non-empty and Recheck is
-// enabled, then all remaining transactions will be rechecked via
-// CheckTx. The order transactions are rechecked must be the same as
-// the order in which this callback is called.
+// handleRecheckResult handles the response from an ABCI CheckTx call issued
+// during the recheck phase of a block Update. It removes the transaction if it
+// was invalidated by the application.
 //
 // This method is NOT executed for the initial CheckTx on a new transaction;
 // that case is handled by addNewTransaction instead.
-func (txmp *TxMempool) handleRecheckResult(tx types.Tx, res *abci.ResponseCheckTxV2) {
-	if txmp.recheckCursor == nil {
-		return
-	}
-
+//
+// NOTE:
+// - The caller must have a write-lock when executing handleRecheckResult.
+func (txmp *TxMempool) handleRecheckResult(wtx *WrappedTx, res *abci.ResponseCheckTxV2) {
 	txmp.metrics.RecheckTimes.Add(1)
 
-	wtx := txmp.recheckCursor.Value.(*WrappedTx)
+	// Only evaluate transactions that have not been removed. This can happen
+	// if an existing transaction is evicted during CheckTx and while this
+	// callback is being executed for the same evicted transaction.
+	if txmp.txStore.IsTxRemoved(wtx) {
+		return
+	}
 
-	// Search through the remaining list of tx to recheck for a transaction that matches
-	// the one we received from the ABCI application.
-	for {
-		if bytes.Equal(tx, wtx.tx) {
-			// We've found a tx in the recheck list that matches the tx that we
-			// received from the ABCI application.
-			// Break, and use this transaction for further checks.
-			break
-		}
+	var err error
+	if txmp.postCheck != nil {
+		err = txmp.postCheck(wtx.tx, res.ResponseCheckTx)
+	}
 
+	// we will treat a transaction that turns pending in a recheck as invalid and evict it
+	if res.Code == abci.CodeTypeOK && err == nil && !res.IsPendingTransaction {
+		wtx.priority = res.Priority
+		wtx.stale = false
+	} else {
 		txmp.logger.Debug(
-			"re-CheckTx transaction mismatch",
-			"got", wtx.tx.Hash(),
-			"expected", tx.Key(),
+			"existing transaction no longer valid; failed re-CheckTx callback",
+			"priority", wtx.priority,
+			"tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+			"err", err,
+			"code", res.Code,
 		)
 
-		if txmp.recheckCursor == txmp.recheckEnd {
-			// we reached the end of the recheckTx list without finding a tx
-			// matching the one we received from the ABCI application.
-			// Return without processing any tx.
-			txmp.recheckCursor = nil
-			return
-		}
-
-		txmp.recheckCursor = txmp.recheckCursor.Next()
-		wtx = txmp.recheckCursor.Value.(*WrappedTx)
+		txmp.removeTx(wtx, types.MempoolTxInvalid, !txmp.config.KeepInvalidTxsInCache, true, true)
 	}
+}
 
-	// Only evaluate transactions that have not been removed. This can happen
-	// if an existing transaction is evicted during CheckTx and while this
-	// callback is being executed for the same evicted transaction.
-	if !txmp.txStore.IsTxRemoved(wtx) {
-		var err error
-		if txmp.postCheck != nil {
-			err = txmp.postCheck(tx, res.ResponseCheckTx)
+// recheckTxs executes CheckTx for each of the given transactions. The
+// responses are buffered and only applied once the app round trips completed,
+// so a connection failure never leaves the mempool partially updated. A failed
+// round trip is not retried, as the caller holds the write lock: the
+// transactions that could not be rechecked are marked stale, so that they are
+// excluded from reaping, and their number is returned. Stale transactions are
+// retried by recoverStaleTxs, which does not hold the lock while it waits for
+// the app connection.
+//
+// NOTE:
+// - The caller must have a write-lock when executing recheckTxs.
+func (txmp *TxMempool) recheckTxs(ctx context.Context, wtxs []*WrappedTx) int {
+	results := make([]*abci.ResponseCheckTxV2, 0, len(wtxs))
+
+	for len(results) < len(wtxs) {
+		wtx := wtxs[len(results)]
+
+		// Only execute CheckTx if the transaction is not marked as removed which
+		// could happen if the transaction was evicted.
+		if txmp.txStore.IsTxRemoved(wtx) {
+			results = append(results, nil)
+			continue
 		}
 
-		// we will treat a transaction that turns pending in a recheck as invalid and evict it
-		if res.Code == abci.CodeTypeOK && err == nil && !res.IsPendingTransaction {
-			wtx.priority = res.Priority
-		} else {
-			txmp.logger.Debug(
-				"existing transaction no longer valid; failed re-CheckTx callback",
-				"priority", wtx.priority,
-				"tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+		res, err := txmp.proxyAppConn.CheckTx(ctx, &abci.RequestCheckTx{
+			Tx:   wtx.tx,
+			Type: abci.CheckTxType_Recheck,
+		})
+		if err != nil {
+			txmp.logger.Error(
+				"failed to execute CheckTx during recheck",
 				"err", err,
-				"code", res.Code,
+				"hash", fmt.Sprintf("%x", wtx.tx.Hash()),
+				"num_rechecked", len(results),
+				"num_txs", len(wtxs),
 			)
+			break
+		}
+		results = append(results, res)
+	}
 
-			if wtx.gossipEl != txmp.recheckCursor {
-				panic("corrupted reCheckTx cursor")
-			}
-
-			txmp.removeTx(wtx, !txmp.config.KeepInvalidTxsInCache, true, true)
+	for i, res := range results {
+		if res != nil {
+			txmp.handleRecheckResult(wtxs[i], res)
 		}
 	}
 
-	// move reCheckTx cursor to next element
-	if txmp.recheckCursor == txmp.recheckEnd {
-		txmp.recheckCursor = nil
-	} else {
-		txmp.recheckCursor = txmp.recheckCursor.Next()
+	stale := wtxs[len(results):]
+	for _, wtx := range stale {
+		wtx.stale = true
 	}
 
-	if txmp.recheckCursor == nil {
-		txmp.logger.Debug("finished rechecking transactions")
+	txmp.logger.Debug("finished rechecking transactions", "num_stale", len(stale))
 
-		if txmp.NumTxsNotPending() > 0 {
-			txmp.notifyTxsAvailable()
-		}
+	if txmp.NumTxsNotPending() > 0 {
+		txmp.notifyTxsAvailable()
 	}
 
 	txmp.metrics.Size.Set(float64(txmp.NumTxsNotPending()))
 	txmp.metrics.PendingSize.Set(float64(txmp.PendingSize()))
 	txmp.metrics.TotalTxsSizeBytes.Set(float64(txmp.TotalTxsBytesSize()))
+	txmp.updateMemoryMetrics()
+
+	return len(stale)
 }
 
-// updateReCheckTxs updates the recheck cursors using the gossipIndex. For
-// each transaction, it executes CheckTx. The global callback defined on
-// the proxyAppConn will be executed for each transaction after CheckTx is
-// executed.
+// updateReCheckTxs rechecks all transactions of the gossip index. If the
+// recheck could not be completed, it is retried in the background, otherwise
+// the mempool is taken out of degraded mode.
 //
 // NOTE:
 // - The caller must have a write-lock when executing updateReCheckTxs.
@@ -814,26 +1257,16 @@ func (txmp *TxMempool) updateReCheckTxs(ctx context.Context) {
 		"height", txmp.height,
 	)
 
-	txmp.recheckCursor = txmp.gossipIndex.Front()
-	txmp.recheckEnd = txmp.gossipIndex.Back()
-
+	wtxs := make([]*WrappedTx, 0, txmp.gossipIndex.Len())
 	for e := txmp.gossipIndex.Front(); e != nil; e = e.Next() {
-		wtx := e.Value.(*WrappedTx)
+		wtxs = append(wtxs, e.Value.(*WrappedTx))
+	}
 
-		// Only execute CheckTx if the transaction is not marked as removed which
-		// could happen if the transaction was evicted.
-		if !txmp.txStore.IsTxRemoved(wtx) {
-			res, err := txmp.proxyAppConn.CheckTx(ctx, &abci.RequestCheckTx{
-				Tx:   wtx.tx,
-				Type: abci.CheckTxType_Recheck,
-			})
-			if err != nil {
-				// no need in retrying since the tx will be rechecked after the next block
-				txmp.logger.Debug("failed to execute CheckTx during recheck", "err", err, "hash", fmt.Sprintf("%x", wtx.tx.Hash()))
-				continue
-			}
-			txmp.handleRecheckResult(wtx.tx, res)
-		}
+	if numStale := txmp.recheckTxs(ctx, wtxs); numStale > 0 {
+		txmp.recoverStale(numStale)
+	} else {
+		// all transactions, including previously stale ones, were rechecked
+		txmp.clearDegraded()
 	}
 
 	if err := txmp.proxyAppConn.Flush(ctx); err != nil {
@@ -841,21 +1274,132 @@ func (txmp *TxMempool) updateReCheckTxs(ctx context.Context) {
 	}
 }
 
+// recoverStale starts a goroutine that retries the recheck of the numStale
+// stale transactions, unless one is already running.
+//
+// NOTE:
+// - The caller must have a write-lock when executing recoverStale.
+func (txmp *TxMempool) recoverStale(numStale int) {
+	txmp.logger.Info("retrying the recheck of stale transactions", "num_stale", numStale, "height", txmp.height)
+
+	if !txmp.recovering {
+		txmp.recovering = true
+		go txmp.recoverStaleTxs(txmp.lifetime, time.Now().Add(txmp.config.RecheckRetryTimeout))
+	}
+}
+
+// setDegraded puts the mempool in degraded mode.
+//
+// NOTE:
+// - The caller must have a write-lock when executing setDegraded.
+func (txmp *TxMempool) setDegraded() {
+	if atomic.SwapInt32(&txmp.degraded, 1) == 0 {
+		var numStale int
+		for e := txmp.gossipIndex.Front(); e != nil; e = e.Next() {
+			if e.Value.(*WrappedTx).stale {
+				numStale++
+			}
+		}
+		txmp.logger.Error(
+			"mempool degraded; transactions could not be rechecked and are excluded from reaping",
+			"num_stale", numStale,
+			"height", txmp.height,
+		)
+	}
+	txmp.metrics.Degraded.Set(1)
+}
+
+// clearDegraded takes the mempool out of degraded mode.
+//
+// NOTE:
+// - The caller must have a write-lock when executing clearDegraded.
+func (txmp *TxMempool) clearDegraded() {
+	if atomic.SwapInt32(&txmp.degraded, 0) == 1 {
+		txmp.logger.Info("mempool recovered; all transactions were rechecked", "height", txmp.height)
+	}
+	txmp.metrics.Degraded.Set(0)
+}
+
+// recoverStaleTxs waits, with exponential backoff, for the app connection to
+// answer an Echo request and then rechecks the stale transactions of the
+// mempool. Once recheckRetryAttempts attempts failed or the deadline passed,
+// the mempool is marked degraded, but the attempts go on. It returns once no
+// transaction is stale anymore or ctx is done.
+func (txmp *TxMempool) recoverStaleTxs(ctx context.Context, deadline time.Time) {
+	defer func() {
+		txmp.Lock()
+		txmp.recovering = false
+		txmp.Unlock()
+	}()
+
+	backoff := recheckRetryMinBackoff
+	for attempt := 1; ; attempt++ {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > recheckRetryMaxBackoff {
+			backoff = recheckRetryMaxBackoff
+		}
+
+		_, err := txmp.proxyAppConn.Echo(ctx, "mempool-recheck")
+
+		txmp.Lock()
+		var stale []*WrappedTx
+		for e := txmp.gossipIndex.Front(); e != nil; e = e.Next() {
+			if wtx := e.Value.(*WrappedTx); wtx.stale {
+				stale = append(stale, wtx)
+			}
+		}
+
+		// a recheck executed by Update in the meantime may have recovered the
+		// mempool
+		recovered := len(stale) == 0
+		if !recovered && err == nil {
+			txmp.logger.Info("app connection available; rechecking stale transactions", "num_stale", len(stale))
+			recovered = txmp.recheckTxs(ctx, stale) == 0
+		}
+
+		if recovered {
+			txmp.clearDegraded()
+		} else if attempt >= recheckRetryAttempts || !time.Now().Before(deadline) {
+			txmp.setDegraded()
+		}
+		txmp.Unlock()
+
+		if recovered {
+			return
+		}
+	}
+}
+
 // canAddTx returns an error if we cannot insert the provided *WrappedTx into
 // the mempool due to mempool configured constraints. If it returns nil,
 // the transaction can be inserted into the mempool.
 func (txmp *TxMempool) canAddTx(wtx *WrappedTx) error {
 	var (
-		numTxs    = txmp.NumTxsNotPending()
-		sizeBytes = txmp.SizeBytes()
+		numTxs      = txmp.NumTxsNotPending()
+		sizeBytes   = txmp.SizeBytes()
+		memoryBytes int64
 	)
+	if txmp.config.MaxMemoryBytes > 0 {
+		memoryBytes = txmp.memoryUsage().total()
+	}
 
-	if numTxs >= txmp.config.Size || int64(wtx.Size())+sizeBytes > txmp.config.MaxTxsBytes {
+	if numTxs >= txmp.config.Size || int64(wtx.Size())+sizeBytes > txmp.config.MaxTxsBytes ||
+		(txmp.config.MaxMemoryBytes > 0 && int64(wtx.Size())+txOverheadBytes+memoryBytes > txmp.config.MaxMemoryBytes) {
 		return types.ErrMempoolIsFull{
-			NumTxs:      numTxs,
-			MaxTxs:      txmp.config.Size,
-			TxsBytes:    sizeBytes,
-			MaxTxsBytes: txmp.config.MaxTxsBytes,
+			NumTxs:         numTxs,
+			MaxTxs:         txmp.config.Size,
+			TxsBytes:       sizeBytes,
+			MaxTxsBytes:    txmp.config.MaxTxsBytes,
+			MemoryBytes:    memoryBytes,
+			MaxMemoryBytes: txmp.config.MaxMemoryBytes,
 		}
 	}
 
@@ -864,16 +1408,25 @@ func (txmp *TxMempool) canAddTx(wtx *WrappedTx) error {
 
 func (txmp *TxMempool) canAddPendingTx(wtx *WrappedTx) error {
 	var (
-		numTxs    = txmp.PendingSize()
-		sizeBytes = txmp.PendingSizeBytes()
+		numTxs      = txmp.PendingSize()
+		sizeBytes   = txmp.PendingSizeBytes()
+		memoryBytes int64
 	)
+	if txmp.config.MaxMemoryBytes > 0 {
+		memoryBytes = txmp.memoryUsage().total()
+	}
 
-	if numTxs >= txmp.config.PendingSize || int64(wtx.Size())+sizeBytes > txmp.config.MaxPendingTxsBytes {
+	// pending txs are never evicted, so a pending tx is only admitted if it
+	// fits within the memory limit as is
+	if numTxs >= txmp.config.PendingSize || int64(wtx.Size())+sizeBytes > txmp.config.MaxPendingTxsBytes ||
+		(txmp.config.MaxMemoryBytes > 0 && int64(wtx.Size())+pendingTxOverheadBytes+memoryBytes > txmp.config.MaxMemoryBytes) {
 		return types.ErrMempoolPendingIsFull{
-			NumTxs:      numTxs,
-			MaxTxs:      txmp.config.PendingSize,
-			TxsBytes:    sizeBytes,
-			MaxTxsBytes: txmp.config.MaxPendingTxsBytes,
+			NumTxs:         numTxs,
+			MaxTxs:         txmp.config.PendingSize,
+			TxsBytes:       sizeBytes,
+			MaxTxsBytes:    txmp.config.MaxPendingTxsBytes,
+			MemoryBytes:    memoryBytes,
+			MaxMemoryBytes: txmp.config.MaxMemoryBytes,
 		}
 	}
 
@@ -889,9 +1442,10 @@ func (txmp *TxMempool) insertTx(wtx *WrappedTx) bool {
 	txmp.metrics.Size.Set(float64(txmp.NumTxsNotPending()))
 	txmp.metrics.PendingSize.Set(float64(txmp.PendingSize()))
 	txmp.metrics.TotalTxsSizeBytes.Set(float64(txmp.TotalTxsBytesSize()))
+	txmp.updateMemoryMetrics()
 
 	if replacedTx != nil {
-		txmp.removeTx(replacedTx, true, false, false)
+		txmp.removeTx(replacedTx, types.MempoolTxReplaced, true, false, false)
 	}
 
 	txmp.txStore.SetTx(wtx)
@@ -906,10 +1460,11 @@ func (txmp *TxMempool) insertTx(wtx *WrappedTx) bool {
 
 	txmp.metrics.InsertedTxs.Add(1)
 	atomic.AddInt64(&txmp.sizeBytes, int64(wtx.Size()))
+	txmp.publishTxAdded(wtx)
 	return true
 }
 
-func (txmp *TxMempool) removeTx(wtx *WrappedTx, removeFromCache bool, shouldReenqueue bool, updatePriorityIndex bool) {
+func (txmp *TxMempool) removeTx(wtx *WrappedTx, reason string, removeFromCache bool, shouldReenqueue bool, updatePriorityIndex bool) {
 	if txmp.txStore.IsTxRemoved(wtx) {
 		return
 	}
@@ -931,15 +1486,20 @@ func (txmp *TxMempool) removeTx(wtx *WrappedTx, removeFromCache bool, shouldReen
 	atomic.AddInt64(&txmp.sizeBytes, int64(-wtx.Size()))
 
 	wtx.removeHandler(removeFromCache)
+	txmp.walRemoved(wtx)
+	txmp.publishTxRemoved(wtx, reason)
 
 	if shouldReenqueue {
 		for _, reenqueue := range toBeReenqueued {
-			txmp.removeTx(reenqueue, removeFromCache, false, true)
+			txmp.removeTx(reenqueue, types.MempoolTxReenqueued, removeFromCache, false, true)
 		}
 		for _, reenqueue := range toBeReenqueued {
 			rtx := reenqueue.tx
+			// Keep the tx private and not gossiped back to its sender, without
+			// charging the sender's quotas again.
+			txInfo := TxInfo{SenderID: reenqueue.senderID, Private: reenqueue.private}
 			go func() {
-				if err := txmp.CheckTx(context.Background(), rtx, nil, TxInfo{}); err != nil {
+				if err := txmp.CheckTx(context.Background(), rtx, nil, txInfo); err != nil {
 					txmp.logger.Error(fmt.Sprintf("failed to reenqueue transaction %X due to %s", rtx.Hash(), err))
 				}
 			}()
@@ -951,6 +1511,7 @@ func (txmp *TxMempool) expire(blockHeight int64, wtx *WrappedTx) {
 	txmp.metrics.ExpiredTxs.Add(1)
 	txmp.logExpiredTx(blockHeight, wtx)
 	wtx.removeHandler(!txmp.config.KeepInvalidTxsInCache)
+	txmp.walRemoved(wtx)
 }
 
 func (txmp *TxMempool) logExpiredTx(blockHeight int64, wtx *WrappedTx) {
@@ -988,7 +1549,7 @@ func (txmp *TxMempool) purgeExpiredTxs(blockHeight int64) {
 		purgeIdx := -1
 		for i, wtx := range txmp.heightIndex.txs {
 			if (blockHeight - wtx.height) > txmp.config.TTLNumBlocks {
-				expiredTxs[wtx.tx.Key()] = wtx
+				expiredTxs[wtx.hash] = wtx
 				purgeIdx = i
 			} else {
 				// since the index is sorted, we know no other txs can be be purged
@@ -1005,7 +1566,7 @@ func (txmp *TxMempool) purgeExpiredTxs(blockHeight int64) {
 		purgeIdx := -1
 		for i, wtx := range txmp.timestampIndex.txs {
 			if now.Sub(wtx.timestamp) > txmp.config.TTLDuration {
-				expiredTxs[wtx.tx.Key()] = wtx
+				expiredTxs[wtx.hash] = wtx
 				purgeIdx = i
 			} else {
 				// since the index is sorted, we know no other txs can be be purged
@@ -1020,6 +1581,7 @@ func (txmp *TxMempool) purgeExpiredTxs(blockHeight int64) {
 
 	for _, wtx := range expiredTxs {
 		txmp.expire(blockHeight, wtx)
+		txmp.publishTxRemoved(wtx, types.MempoolTxExpired)
 	}
 
 	// remove pending txs that have expired
@@ -1078,5 +1640,77 @@ func (txmp *TxMempool) handlePendingTransactions() {
 		if !txmp.config.KeepInvalidTxsInCache {
 			tx.tx.removeHandler(true)
 		}
+		txmp.walRemoved(tx.tx)
+	}
+}
+
+// txEvent is a transaction event queued for the event publisher.
+type txEvent struct {
+	data    types.EventDataMempoolTx
+	removed bool
+}
+
+// start runs the mempool's background routines until ctx is done. It is called
+// by the reactor when it starts.
+func (txmp *TxMempool) start(ctx context.Context) {
+	txmp.Lock()
+	txmp.lifetime = ctx
+	txmp.Unlock()
+
+	go txmp.publishTxEvents(ctx)
+}
+
+// publishTxEvents publishes the queued transaction events until ctx is done.
+func (txmp *TxMempool) publishTxEvents(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case ev := <-txmp.txEvents:
+			if ev.removed {
+				if err := txmp.eventPublisher.PublishEventMempoolTxRemoved(ev.data); err != nil {
+					txmp.logger.Error("failed publishing mempool tx removed event", "err", err)
+				}
+			} else if err := txmp.eventPublisher.PublishEventMempoolTxAdded(ev.data); err != nil {
+				txmp.logger.Error("failed publishing mempool tx added event", "err", err)
+			}
+		}
+	}
+}
+
+// publishTxAdded and publishTxRemoved queue the mempool's transaction events,
+// if enabled. Pending transactions are only reported once they are added to
+// the mempool, and private transactions are never reported. They are called
+// under the lock, so they never block: an event is dropped if the queue is
+// full.
+func (txmp *TxMempool) publishTxAdded(wtx *WrappedTx) {
+	if txmp.eventPublisher == nil || !txmp.config.PublishTxEvents || wtx.private {
+		return
+	}
+	txmp.queueTxEvent(txEvent{data: types.EventDataMempoolTx{
+		Tx:       wtx.tx,
+		Priority: wtx.priority,
+		Height:   wtx.height,
+	}})
+}
+
+func (txmp *TxMempool) publishTxRemoved(wtx *WrappedTx, reason string) {
+	if txmp.eventPublisher == nil || !txmp.config.PublishTxEvents || wtx.private {
+		return
+	}
+	txmp.queueTxEvent(txEvent{removed: true, data: types.EventDataMempoolTx{
+		Tx:       wtx.tx,
+		Priority: wtx.priority,
+		Height:   wtx.height,
+		Reason:   reason,
+	}})
+}
+
+func (txmp *TxMempool) queueTxEvent(ev txEvent) {
+	select {
+	case txmp.txEvents <- ev:
+	default:
+		txmp.metrics.DroppedTxEvents.Add(1)
 	}
 }
@@ -7,14 +7,20 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	abciclient "github.com/tendermint/tendermint/abci/client"
 	"github.com/tendermint/tendermint/abci/example/code"
@@ -665,13 +671,53 @@ func TestTxMempool_EVMEviction(t *testing.T) {
 
 	require.NoError(t, txmp.CheckTx(ctx, []byte(fmt.Sprintf("evm-sender=%s=%d=%d", address2, 5, 1)), nil, TxInfo{SenderID: peerID}))
 	require.Equal(t, 2, txmp.priorityIndex.NumTxs())
-	txmp.removeTx(tx, true, false, true)
+	txmp.removeTx(tx, types.MempoolTxRemoved, true, false, true)
 	// should not reenqueue
 	require.Equal(t, 1, txmp.priorityIndex.NumTxs())
 	time.Sleep(1 * time.Second) // pendingTxs should still be one even after sleeping for a sec
 	require.Equal(t, 1, txmp.pendingTxs.Size())
 }
 
+func TestTxMempool_OldestHeightEviction(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 100)
+	txmp.config.Size = 2
+	txmp.evictionPolicy = evictionPolicies[config.EvictionPolicyOldestHeight]
+
+	txA := types.Tx("sender-a=key=100")
+	txB := types.Tx("sender-b=key=200")
+	require.NoError(t, txmp.CheckTx(ctx, txA, nil, TxInfo{}))
+	require.NoError(t, txmp.CheckTx(ctx, txB, nil, TxInfo{}))
+
+	// txs validated at the same height are never evicted, whatever their
+	// priority
+	txC := types.Tx("sender-c=key=1000")
+	require.NoError(t, txmp.CheckTx(ctx, txC, nil, TxInfo{}))
+	require.Equal(t, 2, txmp.Size())
+	require.False(t, txmp.HasTx(txC.Key()))
+
+	txmp.Lock()
+	require.NoError(t, txmp.Update(ctx, 1, nil, nil, nil, nil, false))
+	txmp.Unlock()
+
+	// a lower priority tx from a later height evicts the lowest priority tx of
+	// the oldest height
+	txD := types.Tx("sender-d=key=1")
+	require.NoError(t, txmp.CheckTx(ctx, txD, nil, TxInfo{}))
+	require.Equal(t, 2, txmp.Size())
+	require.False(t, txmp.HasTx(txA.Key()))
+	require.True(t, txmp.HasTx(txB.Key()))
+	require.True(t, txmp.HasTx(txD.Key()))
+}
+
 func TestTxMempool_CheckTxSamePeer(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -957,6 +1003,602 @@ func TestTxMempool_FailedCheckTxCount(t *testing.T) {
 	require.True(t, txmp.peerManager.(*TestPeerEvictor).IsEvicted("sender"))
 }
 
+func TestTxMempool_ExportImportTxs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 100)
+	txs := convertTex(checkTxs(ctx, t, txmp, 20, 0))
+	require.NoError(t, txmp.CheckTx(ctx, []byte("sender-private=key=1"), nil, TxInfo{Private: true}))
+
+	// txs are exported in arrival order, without the private one
+	exported := txmp.ExportTxs()
+	require.Equal(t, txs, exported)
+
+	other := setup(t, client, 100)
+	accepted, err := other.ImportTxs(ctx, append(exported, []byte("bad tx")))
+	require.NoError(t, err)
+	require.Equal(t, 20, accepted)
+	require.Equal(t, 20, other.Size())
+
+	// known txs are skipped
+	accepted, err = other.ImportTxs(ctx, exported)
+	require.NoError(t, err)
+	require.Zero(t, accepted)
+}
+
+func TestTxMempool_ForEachTx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 100)
+	_ = checkTxs(ctx, t, txmp, 20, 0)
+	require.NoError(t, txmp.CheckTx(ctx, []byte("sender-private=key=1"), nil, TxInfo{Private: true}))
+
+	collect := func(forEach func(func(types.Tx) bool), limit int) types.Txs {
+		var txs types.Txs
+		forEach(func(tx types.Tx) bool {
+			txs = append(txs, tx)
+			return len(txs) < limit
+		})
+		return txs
+	}
+
+	// the iterators visit the txs the slice methods return, in the same order
+	forEachTx := func(fn func(types.Tx) bool) { txmp.ForEachTx(15, fn) }
+	require.Equal(t, txmp.ReapMaxTxs(15), collect(forEachTx, 100))
+	require.Equal(t, txmp.ExportTxs(), collect(txmp.ForEachExportedTx, 100))
+
+	// until fn returns false
+	require.Equal(t, txmp.ReapMaxTxs(5), collect(forEachTx, 5))
+	require.Equal(t, txmp.ExportTxs()[:5], collect(txmp.ForEachExportedTx, 5))
+
+	// fn may use the mempool, as it is called without holding the lock
+	hasTx := func(tx types.Tx) bool { return txmp.HasTx(tx.Key()) }
+	require.Len(t, collect(func(fn func(types.Tx) bool) {
+		txmp.ForEachExportedTx(func(tx types.Tx) bool { return hasTx(tx) && fn(tx) })
+	}, 100), 20)
+	require.Len(t, collect(func(fn func(types.Tx) bool) {
+		forEachTx(func(tx types.Tx) bool { return hasTx(tx) && fn(tx) })
+	}, 100), 15)
+}
+
+type testEventPublisher struct {
+	mtx     sync.Mutex
+	added   []types.EventDataMempoolTx
+	removed []types.EventDataMempoolTx
+}
+
+func (p *testEventPublisher) PublishEventMempoolTxAdded(data types.EventDataMempoolTx) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.added = append(p.added, data)
+	return nil
+}
+
+func (p *testEventPublisher) PublishEventMempoolTxRemoved(data types.EventDataMempoolTx) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.removed = append(p.removed, data)
+	return nil
+}
+
+func (p *testEventPublisher) events() (added, removed []types.EventDataMempoolTx) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return append(added, p.added...), append(removed, p.removed...)
+}
+
+// testCounter is a metrics.Counter recording the sum of its values.
+type testCounter struct {
+	mtx sync.Mutex
+	sum float64
+}
+
+func (c *testCounter) With(...string) metrics.Counter { return c }
+
+func (c *testCounter) Add(delta float64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.sum += delta
+}
+
+func (c *testCounter) value() float64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.sum
+}
+
+// blockingEventPublisher never returns from publishing an event, like an
+// event bus with a subscriber that never drains its events.
+type blockingEventPublisher struct {
+	done chan struct{}
+}
+
+func (p *blockingEventPublisher) PublishEventMempoolTxAdded(types.EventDataMempoolTx) error {
+	<-p.done
+	return nil
+}
+
+func (p *blockingEventPublisher) PublishEventMempoolTxRemoved(types.EventDataMempoolTx) error {
+	<-p.done
+	return nil
+}
+
+func TestTxMempool_TxEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	publisher := &testEventPublisher{}
+	txmp := setup(t, client, 100, WithEventPublisher(publisher))
+	txmp.start(ctx)
+
+	// events are disabled by default
+	require.NoError(t, txmp.CheckTx(ctx, []byte("sender-0=key=1"), nil, TxInfo{}))
+	require.Empty(t, txmp.txEvents)
+
+	txmp.config.PublishTxEvents = true
+
+	// private transactions are never published
+	private := types.Tx("sender-3=key=4")
+	require.NoError(t, txmp.CheckTx(ctx, private, nil, TxInfo{Private: true}))
+	require.True(t, txmp.HasTx(private.Key()))
+	require.NoError(t, txmp.RemoveTxByKey(private.Key()))
+	require.Empty(t, txmp.txEvents)
+
+	committed := types.Tx("sender-1=key=2")
+	removed := types.Tx("sender-2=key=3")
+	require.NoError(t, txmp.CheckTx(ctx, committed, nil, TxInfo{}))
+	require.NoError(t, txmp.CheckTx(ctx, removed, nil, TxInfo{}))
+
+	require.NoError(t, txmp.RemoveTxByKey(removed.Key()))
+	txmp.Lock()
+	require.NoError(t, txmp.Update(ctx, 1, types.Txs{committed}, []*abci.ExecTxResult{{Code: abci.CodeTypeOK}}, nil, nil, true))
+	txmp.Unlock()
+
+	// the events are published in the background, in order
+	require.Eventually(t, func() bool {
+		_, removedEvents := publisher.events()
+		return len(removedEvents) == 2
+	}, time.Second, 10*time.Millisecond)
+	added, removedEvents := publisher.events()
+	require.Len(t, added, 2)
+	require.Equal(t, committed, added[0].Tx)
+	require.Equal(t, int64(2), added[0].Priority)
+	require.Empty(t, added[0].Reason)
+	require.Equal(t, removed, removedEvents[0].Tx)
+	require.Equal(t, types.MempoolTxRemoved, removedEvents[0].Reason)
+	require.Equal(t, committed, removedEvents[1].Tx)
+	require.Equal(t, types.MempoolTxCommitted, removedEvents[1].Reason)
+}
+
+func TestTxMempool_TxEventsNeverBlock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	publisher := &blockingEventPublisher{done: make(chan struct{})}
+	t.Cleanup(func() { close(publisher.done) })
+	dropped := &testCounter{}
+	m := NopMetrics()
+	m.DroppedTxEvents = dropped
+	txmp := setup(t, client, 100, WithEventPublisher(publisher), WithMetrics(m))
+	txmp.config.PublishTxEvents = true
+	txmp.txEvents = make(chan txEvent, 1)
+	txmp.start(ctx)
+
+	// neither CheckTx nor Update wait for the stuck publisher
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		txs := convertTex(checkTxs(ctx, t, txmp, 10, 0))
+		responses := make([]*abci.ExecTxResult, len(txs))
+		for i := range responses {
+			responses[i] = &abci.ExecTxResult{Code: abci.CodeTypeOK}
+		}
+		txmp.Lock()
+		defer txmp.Unlock()
+		require.NoError(t, txmp.Update(ctx, 1, txs, responses, nil, nil, true))
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("mempool blocked on the event publisher")
+	}
+	require.Zero(t, txmp.Size())
+
+	// of the 20 events, at most one is being published and one queued
+	require.GreaterOrEqual(t, dropped.value(), float64(18))
+}
+
+// activeApplication never reports EVM transactions as pending, so that
+// reenqueued transactions go straight back into the mempool.
+type activeApplication struct {
+	*application
+}
+
+func (app *activeApplication) CheckTx(ctx context.Context, req *abci.RequestCheckTx) (*abci.ResponseCheckTxV2, error) {
+	res, err := app.application.CheckTx(ctx, req)
+	if res != nil {
+		res.IsPendingTransaction = false
+	}
+	return res, err
+}
+
+func TestTxMempool_ReenqueueKeepsPrivate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app := &activeApplication{application: &application{Application: kvstore.NewApplication()}}
+	client := abciclient.NewLocalClient(log.NewNopLogger(), app)
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	walDir := t.TempDir()
+	txmp := setup(t, client, 100)
+	txmp.config.WalPath = walDir
+	require.NoError(t, txmp.InitWAL(ctx))
+	t.Cleanup(txmp.CloseWAL)
+
+	address := "0xeD23B3A9DE15e92B9ef9540E587B3661E15A12fA"
+	first := types.Tx(fmt.Sprintf("evm-sender=%s=%d=%d", address, 1, 0))
+	second := types.Tx(fmt.Sprintf("evm-sender=%s=%d=%d", address, 2, 1))
+	txInfo := TxInfo{SenderID: 1, Private: true}
+	require.NoError(t, txmp.CheckTx(ctx, first, nil, txInfo))
+	require.NoError(t, txmp.CheckTx(ctx, second, nil, txInfo))
+
+	// removing the first tx reenqueues the second one
+	txmp.Lock()
+	txmp.removeTx(txmp.txStore.GetTxByHash(first.Key()), types.MempoolTxRemoved, true, true, true)
+	txmp.Unlock()
+	require.Eventually(t, func() bool { return txmp.HasTx(second.Key()) }, 5*time.Second, 10*time.Millisecond)
+
+	// the reenqueued tx is still private: it is not gossiped, exposed nor
+	// logged
+	txmp.Lock()
+	wtx := txmp.txStore.GetTxByHash(second.Key())
+	txmp.Unlock()
+	require.True(t, wtx.private)
+	require.True(t, txmp.txStore.TxHasPeer(second.Key(), 1))
+	require.Empty(t, txmp.ReapMaxTxs(-1))
+	require.Empty(t, txmp.ExportTxs())
+
+	txmp.CloseWAL()
+	logged, _, err := readWAL(filepath.Join(walDir, walFileName), txmp.config.MaxTxBytes)
+	require.NoError(t, err)
+	require.Empty(t, logged)
+}
+
+func TestTxMempool_UpdateConfig(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 100)
+	_ = checkTxs(ctx, t, txmp, 5, 0)
+
+	// an invalid config is rejected and leaves the current one in place
+	err := txmp.UpdateConfig(func(cfg *config.MempoolConfig) { cfg.Size = -1 })
+	require.Error(t, err)
+	require.NotEqual(t, -1, txmp.config.Size)
+
+	// settings that are only read at startup can't be changed
+	maxTxBytes := txmp.config.MaxTxBytes
+	err = txmp.UpdateConfig(func(cfg *config.MempoolConfig) { cfg.MaxTxBytes *= 2 })
+	require.ErrorContains(t, err, "max-tx-bytes")
+	require.Equal(t, maxTxBytes, txmp.config.MaxTxBytes)
+
+	// lowering the size limit does not evict, but rejects new txs
+	require.NoError(t, txmp.UpdateConfig(func(cfg *config.MempoolConfig) {
+		cfg.Size = 5
+		cfg.PeerTxRateLimit = 1
+	}))
+	require.Equal(t, 5, txmp.Size())
+	require.NotNil(t, txmp.rateLimiter)
+
+	_ = txmp.CheckTx(ctx, []byte("sender-a=key=1"), nil, TxInfo{})
+	require.Equal(t, 5, txmp.Size())
+
+	require.NoError(t, txmp.UpdateConfig(func(cfg *config.MempoolConfig) { cfg.Size = 10 }))
+	require.NoError(t, txmp.CheckTx(ctx, []byte("sender-c=key=1"), nil, TxInfo{SenderNodeID: "peer"}))
+	require.ErrorAs(t, txmp.CheckTx(ctx, []byte("sender-b=key=1"), nil, TxInfo{SenderNodeID: "peer"}), &types.ErrRateLimited{})
+	require.Equal(t, 6, txmp.Size())
+}
+
+func TestTxMempool_Tracing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	recorder := tracetest.NewSpanRecorder()
+	txmp := setup(t, client, 100, WithTracerProviderOptions([]sdktrace.TracerProviderOption{
+		sdktrace.WithSpanProcessor(recorder),
+	}))
+
+	tx := types.Tx("sender-0=key=1")
+	require.NoError(t, txmp.CheckTx(ctx, tx, nil, TxInfo{SenderNodeID: "peer"}))
+	require.ErrorIs(t, txmp.CheckTx(ctx, tx, nil, TxInfo{}), types.ErrTxInCache)
+
+	txmp.Lock()
+	require.NoError(t, txmp.Update(ctx, 1, types.Txs{tx}, []*abci.ExecTxResult{{Code: abci.CodeTypeOK}}, nil, nil, true))
+	txmp.Unlock()
+
+	spans := recorder.Ended()
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name()
+	}
+	require.Equal(t, []string{"mempool.CheckTx.app", "mempool.CheckTx", "mempool.CheckTx", "mempool.Update"}, names)
+
+	// the app span is a child of the CheckTx span
+	require.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	require.Contains(t, spans[1].Attributes(), attribute.String("peer", "peer"))
+	require.Contains(t, spans[1].Attributes(), attribute.String("tx", fmt.Sprintf("%X", tx.Hash())))
+
+	// the duplicate tx is only checked against the cache
+	require.Len(t, spans[2].Events(), 1)
+	require.Equal(t, "exception", spans[2].Events()[0].Name)
+}
+
+func TestTxMempool_CheckTxOverloaded(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	// every CheckTx call is slower than the threshold
+	txmp := setup(t, client, 100)
+	txmp.config.CheckTxLatencyThreshold = time.Nanosecond
+	txmp.config.CheckTxBreakerCooldown = time.Hour
+	txmp.breaker = newCircuitBreaker(txmp.config)
+	require.Equal(t, "closed", txmp.CheckTxBreakerState())
+
+	require.NoError(t, txmp.CheckTx(ctx, []byte("sender-0=key=1"), nil, TxInfo{}))
+	require.Equal(t, 1, txmp.Size())
+	require.Equal(t, "open", txmp.CheckTxBreakerState())
+
+	tx := []byte("sender-1=key=1")
+	err := txmp.CheckTx(ctx, tx, nil, TxInfo{})
+	require.ErrorAs(t, err, &types.ErrAppOverloaded{})
+	require.Equal(t, 1, txmp.Size())
+
+	// the rejected tx was not cached, so it can be resubmitted once the
+	// application recovers
+	txmp.breaker = nil
+	require.NoError(t, txmp.CheckTx(ctx, tx, nil, TxInfo{}))
+	require.Equal(t, 2, txmp.Size())
+}
+
+// erroringAppConn fails CheckTx calls with err, if set.
+type erroringAppConn struct {
+	abciclient.Client
+
+	err error
+}
+
+func (c *erroringAppConn) CheckTx(ctx context.Context, req *abci.RequestCheckTx) (*abci.ResponseCheckTxV2, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.Client.CheckTx(ctx, req)
+}
+
+func TestTxMempool_CheckTxBreakerIgnoresCanceledCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	conn := &erroringAppConn{Client: client}
+	txmp := setup(t, conn, 100)
+	txmp.config.CheckTxLatencyThreshold = time.Hour
+	txmp.config.CheckTxBreakerCooldown = time.Hour
+	txmp.breaker = newCircuitBreaker(txmp.config)
+
+	// callers giving up on their CheckTx do not open the breaker
+	tx := []byte("sender-0=key=1")
+	for _, err := range []error{context.Canceled, context.DeadlineExceeded} {
+		conn.err = err
+		require.ErrorIs(t, txmp.CheckTx(ctx, tx, nil, TxInfo{}), err)
+	}
+	conn.err = nil
+	require.NoError(t, txmp.CheckTx(ctx, tx, nil, TxInfo{}))
+	require.Equal(t, 1, txmp.Size())
+
+	// failures of the application do
+	conn.err = errors.New("app failed")
+	require.Error(t, txmp.CheckTx(ctx, []byte("sender-1=key=1"), nil, TxInfo{}))
+	conn.err = nil
+	require.ErrorAs(t, txmp.CheckTx(ctx, []byte("sender-2=key=1"), nil, TxInfo{}), &types.ErrAppOverloaded{})
+}
+
+func TestTxMempool_CheckTxRateLimited(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 100)
+	txmp.config.PeerTxRateLimit = 5
+	txmp.rateLimiter = newPeerRateLimiter(txmp.config)
+	txInfo := TxInfo{SenderID: 1, SenderNodeID: "sender"}
+
+	for i := 0; i < 5; i++ {
+		tx := []byte(fmt.Sprintf("sender-%d=key=%d", i, i))
+		require.NoError(t, txmp.CheckTx(ctx, tx, nil, txInfo))
+	}
+
+	tx := []byte("sender-5=key=5")
+	err := txmp.CheckTx(ctx, tx, nil, txInfo)
+	require.ErrorAs(t, err, &types.ErrRateLimited{})
+	require.Equal(t, 5, txmp.Size())
+
+	// the rejected tx was not cached, so other peers can still submit it
+	require.NoError(t, txmp.CheckTx(ctx, tx, nil, TxInfo{SenderID: 2, SenderNodeID: "other"}))
+	require.Equal(t, 6, txmp.Size())
+
+	// duplicates are not charged against the quota
+	require.ErrorIs(t, txmp.CheckTx(ctx, tx, nil, txInfo), types.ErrTxInCache)
+
+	// RPC submissions are charged against the RPC quota
+	txmp.config.RPCTxRateLimit = 1
+	txmp.rateLimiter = newPeerRateLimiter(txmp.config)
+	require.NoError(t, txmp.CheckTx(ctx, []byte("sender-6=key=6"), nil, TxInfo{RPC: true}))
+	require.ErrorAs(t, txmp.CheckTx(ctx, []byte("sender-7=key=7"), nil, TxInfo{RPC: true}), &types.ErrRateLimited{})
+	require.NoError(t, txmp.CheckTx(ctx, []byte("sender-8=key=8"), nil, TxInfo{}))
+}
+
+// countingApplication counts the CheckTx calls it receives.
+type countingApplication struct {
+	*application
+
+	checkTxs int32
+}
+
+func (app *countingApplication) CheckTx(ctx context.Context, req *abci.RequestCheckTx) (*abci.ResponseCheckTxV2, error) {
+	atomic.AddInt32(&app.checkTxs, 1)
+	return app.application.CheckTx(ctx, req)
+}
+
+func TestTxMempool_RejectionCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app := &countingApplication{application: &application{Application: kvstore.NewApplication()}}
+	client := abciclient.NewLocalClient(log.NewNopLogger(), app)
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	now := time.Now()
+	txmp := setup(t, client, 100)
+	txmp.config.RejectionCacheSize = 10
+	txmp.config.CacheTTL = time.Minute
+	txmp.rejections = newRejectionCache(txmp.config)
+	txmp.rejections.now = func() time.Time { return now }
+
+	checkBadTx := func() {
+		t.Helper()
+		var res *abci.ResponseCheckTx
+		require.NoError(t, txmp.CheckTx(ctx, []byte("bad"), func(r *abci.ResponseCheckTx) { res = r }, TxInfo{SenderNodeID: "peer"}))
+		require.NotNil(t, res)
+		require.Equal(t, uint32(101), res.Code)
+	}
+
+	// rejections with other codes than the configured ones may be transient,
+	// so they are not cached
+	checkBadTx()
+	checkBadTx()
+	require.Equal(t, int32(2), atomic.LoadInt32(&app.checkTxs))
+
+	txmp.config.RejectionCacheCodes = []uint32{101}
+	txmp.rejections = newRejectionCache(txmp.config)
+	txmp.rejections.now = func() time.Time { return now }
+
+	checkBadTx()
+	require.Equal(t, int32(3), atomic.LoadInt32(&app.checkTxs))
+
+	// resubmissions are answered from the cache, but still count against the
+	// sender
+	checkBadTx()
+	checkBadTx()
+	require.Equal(t, int32(3), atomic.LoadInt32(&app.checkTxs))
+	require.Equal(t, uint64(5), txmp.GetPeerFailedCheckTxCount("peer"))
+
+	// rejections are forgotten after the TTL
+	now = now.Add(time.Minute)
+	checkBadTx()
+	require.Equal(t, int32(4), atomic.LoadInt32(&app.checkTxs))
+
+	// and on every block
+	txmp.Lock()
+	require.NoError(t, txmp.Update(ctx, 1, nil, nil, nil, nil, true))
+	txmp.Unlock()
+	checkBadTx()
+	require.Equal(t, int32(5), atomic.LoadInt32(&app.checkTxs))
+
+	// valid transactions are not affected
+	require.NoError(t, txmp.CheckTx(ctx, []byte("sender-0=key=1"), nil, TxInfo{}))
+	require.Equal(t, 1, txmp.Size())
+}
+
+func TestTxMempool_CacheTTLKeepsMempoolTxs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app := &countingApplication{application: &application{Application: kvstore.NewApplication()}}
+	client := abciclient.NewLocalClient(log.NewNopLogger(), app)
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	now := time.Now()
+	txmp := setup(t, client, 100)
+	cache := NewLRUTxCacheWithTTL(100, time.Minute)
+	cache.now = func() time.Time { return now }
+	txmp.cache = cache
+
+	tx := types.Tx("sender-0=key=1")
+	require.NoError(t, txmp.CheckTx(ctx, tx, nil, TxInfo{}))
+	require.Equal(t, int32(1), atomic.LoadInt32(&app.checkTxs))
+
+	// a tx still in the mempool is not checked again once its cache entry
+	// expired
+	now = now.Add(time.Minute)
+	require.ErrorIs(t, txmp.CheckTx(ctx, tx, nil, TxInfo{}), types.ErrTxInCache)
+	require.Equal(t, int32(1), atomic.LoadInt32(&app.checkTxs))
+	require.Equal(t, 1, txmp.Size())
+}
+
 func TestAppendCheckTxErr(t *testing.T) {
 	// Setup
 	ctx, cancel := context.WithCancel(context.Background())
@@ -982,3 +1624,176 @@ func TestAppendCheckTxErr(t *testing.T) {
 
 	require.Equal(t, newLogData, actualResult)
 }
+
+// flakyAppConn wraps an app connection and lets tests kill and restore it.
+// While killed, CheckTx and Echo fail. Recheck requests for the transactions
+// in invalid are answered with a failure code.
+type flakyAppConn struct {
+	abciclient.Client
+
+	mtx       sync.Mutex
+	killAfter int // number of rechecks after which the connection dies (-1: never)
+	killed    bool
+	rechecks  int
+	rechecked map[types.TxKey]bool
+	invalid   map[types.TxKey]bool
+}
+
+var errAppConnKilled = errors.New("app connection killed")
+
+func (c *flakyAppConn) CheckTx(ctx context.Context, req *abci.RequestCheckTx) (*abci.ResponseCheckTxV2, error) {
+	c.mtx.Lock()
+	if req.Type == abci.CheckTxType_Recheck && c.killAfter >= 0 && c.rechecks >= c.killAfter {
+		c.killed = true
+	}
+	if c.killed {
+		c.mtx.Unlock()
+		return nil, errAppConnKilled
+	}
+	invalid := req.Type == abci.CheckTxType_Recheck && c.invalid[types.Tx(req.Tx).Key()]
+	if req.Type == abci.CheckTxType_Recheck {
+		c.rechecks++
+		c.rechecked[types.Tx(req.Tx).Key()] = true
+	}
+	c.mtx.Unlock()
+
+	if invalid {
+		return &abci.ResponseCheckTxV2{ResponseCheckTx: &abci.ResponseCheckTx{Code: 1}}, nil
+	}
+	return c.Client.CheckTx(ctx, req)
+}
+
+func (c *flakyAppConn) Echo(ctx context.Context, msg string) (*abci.ResponseEcho, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.killed {
+		return nil, errAppConnKilled
+	}
+	return &abci.ResponseEcho{Message: msg}, nil
+}
+
+func (c *flakyAppConn) kill(afterRechecks int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.killAfter = c.rechecks + afterRechecks
+}
+
+func (c *flakyAppConn) restore() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.killAfter = -1
+	c.killed = false
+}
+
+func TestTxMempool_RecheckAppConnFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	conn := &flakyAppConn{
+		Client:    client,
+		killAfter: -1,
+		rechecked: map[types.TxKey]bool{},
+		invalid:   map[types.TxKey]bool{},
+	}
+	txmp := setup(t, conn, 0)
+
+	txs := checkTxs(ctx, t, txmp, 100, 0)
+	require.Equal(t, 100, txmp.Size())
+
+	// Every third transaction becomes invalid with the next block, so a stale
+	// verdict would wrongly keep it reapable.
+	for i, tx := range txs {
+		if i%3 == 0 {
+			conn.invalid[tx.tx.Key()] = true
+		}
+	}
+
+	// The connection dies after 40 rechecks.
+	conn.kill(40)
+	txmp.Lock()
+	require.NoError(t, txmp.Update(ctx, 1, nil, nil, nil, nil, true))
+	txmp.Unlock()
+
+	// The mempool is only degraded once the recheck was retried in vain, but
+	// the transactions that were not rechecked are never reaped meanwhile.
+	require.False(t, txmp.IsDegraded())
+	require.Equal(t, 40, conn.rechecks)
+	var numValid int
+	for key := range conn.rechecked {
+		if !conn.invalid[key] {
+			numValid++
+		}
+	}
+	reaped := txmp.ReapMaxTxs(-1)
+	require.Len(t, reaped, numValid)
+	for _, tx := range reaped {
+		require.False(t, conn.invalid[tx.Key()], "reaped invalid tx")
+		require.True(t, conn.rechecked[tx.Key()], "reaped tx which was not rechecked")
+	}
+	require.Len(t, txmp.ReapMaxBytesMaxGas(-1, -1), numValid)
+	require.Eventually(t, txmp.IsDegraded, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, txmp.ReapMaxTxs(-1), numValid)
+
+	// Once the connection is restored, the stale transactions are rechecked
+	// in the background and the mempool recovers.
+	conn.restore()
+	require.Eventually(t, func() bool { return !txmp.IsDegraded() }, 5*time.Second, 10*time.Millisecond)
+
+	reaped = txmp.ReapMaxTxs(-1)
+	require.Len(t, reaped, 100-34)
+	for _, tx := range reaped {
+		require.False(t, conn.invalid[tx.Key()], "reaped invalid tx")
+	}
+	require.Len(t, txmp.ReapMaxBytesMaxGas(-1, -1), 100-34)
+}
+
+func TestTxMempool_RecheckAppConnTransientFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	conn := &flakyAppConn{
+		Client:    client,
+		killAfter: -1,
+		rechecked: map[types.TxKey]bool{},
+		invalid:   map[types.TxKey]bool{},
+	}
+	txmp := setup(t, conn, 0)
+
+	txmp.config.RecheckRetryTimeout = time.Minute
+	txmp.start(ctx)
+
+	txs := checkTxs(ctx, t, txmp, 100, 0)
+	conn.invalid[txs[90].tx.Key()] = true
+
+	// The connection dies mid-recheck. Update does not wait for it under the
+	// lock: the remaining transactions are left stale, but are still counted.
+	conn.kill(50)
+	updateCtx, updateCancel := context.WithCancel(ctx)
+	txmp.Lock()
+	require.NoError(t, txmp.Update(updateCtx, 1, nil, nil, nil, nil, true))
+	txmp.Unlock()
+	updateCancel()
+
+	require.Equal(t, 100, txmp.Size())
+	require.Len(t, txmp.ReapMaxTxs(-1), 50)
+
+	// Once the connection comes back, the stale transactions are rechecked in
+	// the background, even though Update returned, and the mempool is never
+	// degraded.
+	conn.restore()
+	require.Eventually(t, func() bool { return txmp.Size() == 99 }, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, txmp.ReapMaxTxs(-1), 99)
+	require.False(t, txmp.IsDegraded())
+}
@@ -38,6 +38,12 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "total_txs_size_bytes",
 			Help:      "Total current mempool uncommitted txs bytes",
 		}, labels).With(labelsAndValues...),
+		MemoryBytes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "memory_bytes",
+			Help:      "Estimated memory held by the mempool, by component.",
+		}, append(labels, "component")).With(labelsAndValues...),
 		FailedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -62,6 +68,42 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "expired_txs",
 			Help:      "Number of expired transactions.",
 		}, labels).With(labelsAndValues...),
+		RateLimitedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rate_limited_txs",
+			Help:      "Number of rate limited transactions.",
+		}, append(labels, "source")).With(labelsAndValues...),
+		CheckTxBreakerState: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "check_tx_breaker_state",
+			Help:      "The state of the CheckTx circuit breaker: closed (0), open (1) or half-open (2).",
+		}, labels).With(labelsAndValues...),
+		OverloadedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "overloaded_txs",
+			Help:      "Number of transactions rejected while the application was overloaded.",
+		}, labels).With(labelsAndValues...),
+		DroppedTxEvents: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "dropped_tx_events",
+			Help:      "Number of transaction events dropped because the event queue was full.",
+		}, labels).With(labelsAndValues...),
+		CacheHits: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "cache_hits",
+			Help:      "Number of incoming transactions found in the mempool caches.",
+		}, append(labels, "cache")).With(labelsAndValues...),
+		CacheMisses: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "cache_misses",
+			Help:      "Number of incoming transactions not found in the mempool caches.",
+		}, append(labels, "cache")).With(labelsAndValues...),
 		RecheckTimes: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -80,21 +122,35 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "inserted_txs",
 			Help:      "Number of txs inserted to mempool",
 		}, labels).With(labelsAndValues...),
+		Degraded: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "degraded",
+			Help:      "Whether the mempool is degraded (1) or not (0).",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
 func NopMetrics() *Metrics {
 	return &Metrics{
-		Size:              discard.NewGauge(),
-		PendingSize:       discard.NewGauge(),
-		TxSizeBytes:       discard.NewCounter(),
-		TotalTxsSizeBytes: discard.NewGauge(),
-		FailedTxs:         discard.NewCounter(),
-		RejectedTxs:       discard.NewCounter(),
-		EvictedTxs:        discard.NewCounter(),
-		ExpiredTxs:        discard.NewCounter(),
-		RecheckTimes:      discard.NewCounter(),
-		RemovedTxs:        discard.NewCounter(),
-		InsertedTxs:       discard.NewCounter(),
+		Size:                discard.NewGauge(),
+		PendingSize:         discard.NewGauge(),
+		TxSizeBytes:         discard.NewCounter(),
+		TotalTxsSizeBytes:   discard.NewGauge(),
+		MemoryBytes:         discard.NewGauge(),
+		FailedTxs:           discard.NewCounter(),
+		RejectedTxs:         discard.NewCounter(),
+		EvictedTxs:          discard.NewCounter(),
+		ExpiredTxs:          discard.NewCounter(),
+		RateLimitedTxs:      discard.NewCounter(),
+		CheckTxBreakerState: discard.NewGauge(),
+		OverloadedTxs:       discard.NewCounter(),
+		DroppedTxEvents:     discard.NewCounter(),
+		CacheHits:           discard.NewCounter(),
+		CacheMisses:         discard.NewCounter(),
+		RecheckTimes:        discard.NewCounter(),
+		RemovedTxs:          discard.NewCounter(),
+		InsertedTxs:         discard.NewCounter(),
+		Degraded:            discard.NewGauge(),
 	}
 }
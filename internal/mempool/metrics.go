@@ -27,6 +27,14 @@ type Metrics struct {
 	// Total current mempool uncommitted txs bytes
 	TotalTxsSizeBytes metrics.Gauge
 
+	// MemoryBytes defines the estimated memory held by the mempool, by
+	// component: the bytes of the transactions, including the pending ones
+	// ("txs"), the memory held for each of them besides their bytes
+	// ("overhead"), the cache of seen transactions ("cache") and the
+	// rejection cache ("rejection_cache").
+	//metrics:Estimated memory held by the mempool, by component.
+	MemoryBytes metrics.Gauge `metrics_labels:"component"`
+
 	// Number of failed transactions.
 	FailedTxs metrics.Counter
 
@@ -50,6 +58,38 @@ type Metrics struct {
 	//metrics:Number of expired transactions.
 	ExpiredTxs metrics.Counter
 
+	// RateLimitedTxs defines the number of transactions rejected before
+	// CheckTx because their source exceeded its rate limit: a peer gossiping
+	// them ("peer") or the RPC ("rpc").
+	//metrics:Number of rate limited transactions.
+	RateLimitedTxs metrics.Counter `metrics_labels:"source"`
+
+	// CheckTxBreakerState defines the state of the CheckTx circuit breaker.
+	//metrics:The state of the CheckTx circuit breaker: closed (0), open (1) or half-open (2).
+	CheckTxBreakerState metrics.Gauge
+
+	// OverloadedTxs defines the number of transactions rejected because the
+	// CheckTx circuit breaker was open.
+	//metrics:Number of transactions rejected while the application was overloaded.
+	OverloadedTxs metrics.Counter
+
+	// DroppedTxEvents defines the number of transaction events that were not
+	// published because the event queue was full, i.e. the event bus did not
+	// keep up.
+	//metrics:Number of transaction events dropped because the event queue was full.
+	DroppedTxEvents metrics.Counter
+
+	// CacheHits defines the number of incoming transactions found in one of
+	// the mempool's caches: the cache of seen transactions ("seen") or of
+	// transactions rejected by CheckTx ("rejection").
+	//metrics:Number of incoming transactions found in the mempool caches.
+	CacheHits metrics.Counter `metrics_labels:"cache"`
+
+	// CacheMisses defines the number of incoming transactions not found in one
+	// of the mempool's caches.
+	//metrics:Number of incoming transactions not found in the mempool caches.
+	CacheMisses metrics.Counter `metrics_labels:"cache"`
+
 	// Number of times transactions are rechecked in the mempool.
 	RecheckTimes metrics.Counter
 
@@ -58,4 +98,9 @@ type Metrics struct {
 
 	// Number of txs inserted to mempool
 	InsertedTxs metrics.Counter
+
+	// Degraded is set to 1 while the mempool holds transactions that could not
+	// be rechecked after the latest block because the app connection failed.
+	//metrics:Whether the mempool is degraded (1) or not (0).
+	Degraded metrics.Gauge
 }
@@ -7,6 +7,8 @@ import (
 
 	abcitypes "github.com/tendermint/tendermint/abci/types"
 
+	config "github.com/tendermint/tendermint/config"
+
 	mempool "github.com/tendermint/tendermint/internal/mempool"
 
 	mock "github.com/stretchr/testify/mock"
@@ -33,11 +35,41 @@ func (_m *Mempool) CheckTx(ctx context.Context, tx types.Tx, callback func(*abci
 	return r0
 }
 
+// CheckTxBreakerState provides a mock function with given fields:
+func (_m *Mempool) CheckTxBreakerState() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // EnableTxsAvailable provides a mock function with given fields:
 func (_m *Mempool) EnableTxsAvailable() {
 	_m.Called()
 }
 
+// ExportTxs provides a mock function with given fields:
+func (_m *Mempool) ExportTxs() types.Txs {
+	ret := _m.Called()
+
+	var r0 types.Txs
+	if rf, ok := ret.Get(0).(func() types.Txs); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.Txs)
+		}
+	}
+
+	return r0
+}
+
 // Flush provides a mock function with given fields:
 func (_m *Mempool) Flush() {
 	_m.Called()
@@ -57,6 +89,16 @@ func (_m *Mempool) FlushAppConn(_a0 context.Context) error {
 	return r0
 }
 
+// ForEachExportedTx provides a mock function with given fields: fn
+func (_m *Mempool) ForEachExportedTx(fn func(types.Tx) bool) {
+	_m.Called(fn)
+}
+
+// ForEachTx provides a mock function with given fields: max, fn
+func (_m *Mempool) ForEachTx(max int, fn func(types.Tx) bool) {
+	_m.Called(max, fn)
+}
+
 // GetTxsForKeys provides a mock function with given fields: txKeys
 func (_m *Mempool) GetTxsForKeys(txKeys []types.TxKey) types.Txs {
 	ret := _m.Called(txKeys)
@@ -87,6 +129,41 @@ func (_m *Mempool) HasTx(txKey types.TxKey) bool {
 	return r0
 }
 
+// ImportTxs provides a mock function with given fields: ctx, txs
+func (_m *Mempool) ImportTxs(ctx context.Context, txs types.Txs) (int, error) {
+	ret := _m.Called(ctx, txs)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, types.Txs) int); ok {
+		r0 = rf(ctx, txs)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, types.Txs) error); ok {
+		r1 = rf(ctx, txs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsDegraded provides a mock function with given fields:
+func (_m *Mempool) IsDegraded() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // Lock provides a mock function with given fields:
 func (_m *Mempool) Lock() {
 	_m.Called()
@@ -217,6 +294,20 @@ func (_m *Mempool) Update(ctx context.Context, blockHeight int64, blockTxs types
 	return r0
 }
 
+// UpdateConfig provides a mock function with given fields: update
+func (_m *Mempool) UpdateConfig(update func(*config.MempoolConfig)) error {
+	ret := _m.Called(update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(func(*config.MempoolConfig)) error); ok {
+		r0 = rf(update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewMempool interface {
 	mock.TestingT
 	Cleanup(func())
@@ -97,12 +97,14 @@ func (pq *TxPriorityQueue) tryReplacementUnsafe(tx *WrappedTx) (replaced *Wrappe
 }
 
 // GetEvictableTxs attempts to find and return a list of *WrappedTx than can be
-// evicted to make room for another *WrappedTx with higher priority. If no such
-// list of *WrappedTx exists, nil will be returned. The returned list of *WrappedTx
-// indicate that these transactions can be removed due to them being of lower
-// priority and that their total sum in size allows room for the incoming
-// transaction according to the mempool's configured limits.
-func (pq *TxPriorityQueue) GetEvictableTxs(priority, txSize, totalSize, cap int64) []*WrappedTx {
+// evicted to make room for the incoming *WrappedTx according to the given
+// eviction policy. If no such list of *WrappedTx exists, nil will be returned.
+// The returned list of *WrappedTx indicate that these transactions can be
+// removed in favor of the incoming one and that their total sum in size allows
+// room for the incoming transaction according to the mempool's configured
+// limits. txOverhead is added to the size of every transaction, for limits
+// that also count the memory held for each of them.
+func (pq *TxPriorityQueue) GetEvictableTxs(policy evictionPolicy, incoming *WrappedTx, totalSize, cap, txOverhead int64) []*WrappedTx {
 	pq.mtx.RLock()
 	defer pq.mtx.RUnlock()
 
@@ -113,29 +115,29 @@ func (pq *TxPriorityQueue) GetEvictableTxs(priority, txSize, totalSize, cap int6
 	}
 
 	sort.Slice(txs, func(i, j int) bool {
-		return txs[i].priority < txs[j].priority
+		return policy.less(txs[i], txs[j])
 	})
 
-	var (
-		toEvict []*WrappedTx
-		i       int
-	)
+	var toEvict []*WrappedTx
 
 	currSize := totalSize
+	txSize := int64(incoming.Size()) + txOverhead
 
-	// Loop over all transactions in ascending priority order evaluating those
-	// that are only of less priority than the provided argument. We continue
+	// Loop over all transactions in eviction order evaluating those that the
+	// policy allows to evict in favor of the incoming transaction. We continue
 	// evaluating transactions until there is sufficient capacity for the new
 	// transaction (size) as defined by txSize.
-	for i < len(txs) && txs[i].priority < priority {
-		toEvict = append(toEvict, txs[i])
-		currSize -= int64(txs[i].Size())
+	for _, tx := range txs {
+		if !policy.canEvict(tx, incoming) {
+			continue
+		}
+
+		toEvict = append(toEvict, tx)
+		currSize -= int64(tx.Size()) + txOverhead
 
 		if currSize+txSize <= cap {
 			return toEvict
 		}
-
-		i++
 	}
 
 	return nil
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/config"
 )
 
 // TxTestCase represents a single test case for the TxPriorityQueue
@@ -303,12 +305,68 @@ func TestTxPriorityQueue_GetEvictableTxs(t *testing.T) {
 		tc := tc
 
 		t.Run(tc.name, func(t *testing.T) {
-			evictTxs := pq.GetEvictableTxs(tc.priority, tc.txSize, tc.totalSize, tc.cap)
+			incoming := &WrappedTx{priority: tc.priority, tx: make([]byte, tc.txSize)}
+			evictTxs := pq.GetEvictableTxs(evictionPolicies[config.EvictionPolicyPriority], incoming, tc.totalSize, tc.cap, 0)
 			require.Len(t, evictTxs, tc.expectedLen)
 		})
 	}
 }
 
+func TestTxPriorityQueue_GetEvictableTxsPolicies(t *testing.T) {
+	pq := NewTxPriorityQueue()
+	now := time.Now()
+
+	// each tx is 5 bytes
+	residents := []*WrappedTx{
+		{tx: []byte("tx--a"), priority: 10, height: 3, timestamp: now.Add(1 * time.Second)},
+		{tx: []byte("tx--b"), priority: 20, height: 1, timestamp: now.Add(4 * time.Second)},
+		{tx: []byte("tx--c"), priority: 30, height: 2, timestamp: now.Add(2 * time.Second)},
+		{tx: []byte("tx--d"), priority: 40, height: 1, timestamp: now.Add(3 * time.Second)},
+		{tx: []byte("tx--e"), priority: 50, height: 4, timestamp: now.Add(5 * time.Second)},
+	}
+	for _, wtx := range residents {
+		wtx.hash = wtx.tx.Key()
+		pq.PushTx(wtx)
+	}
+	totalSize := int64(5 * len(residents))
+
+	testCases := []struct {
+		policy   string
+		incoming *WrappedTx
+		expected []string
+	}{
+		{config.EvictionPolicyPriority, &WrappedTx{tx: make([]byte, 5), priority: 25, height: 4}, []string{"tx--a"}},
+		{config.EvictionPolicyPriority, &WrappedTx{tx: make([]byte, 10), priority: 25, height: 4}, []string{"tx--a", "tx--b"}},
+		{config.EvictionPolicyPriority, &WrappedTx{tx: make([]byte, 15), priority: 25, height: 4}, nil},
+		{config.EvictionPolicyPriority, &WrappedTx{tx: make([]byte, 5), priority: 10, height: 4}, nil},
+		{config.EvictionPolicyOldestHeight, &WrappedTx{tx: make([]byte, 10), priority: 0, height: 4}, []string{"tx--b", "tx--d"}},
+		{config.EvictionPolicyOldestHeight, &WrappedTx{tx: make([]byte, 15), priority: 0, height: 3}, []string{"tx--b", "tx--d", "tx--c"}},
+		{config.EvictionPolicyOldestHeight, &WrappedTx{tx: make([]byte, 5), priority: 100, height: 1}, nil},
+		{config.EvictionPolicyFIFO, &WrappedTx{tx: make([]byte, 15), priority: 0, height: 0}, []string{"tx--a", "tx--c", "tx--d"}},
+		{config.EvictionPolicyFIFO, &WrappedTx{tx: make([]byte, 30), priority: 100, height: 5}, nil},
+		// an empty policy is the priority policy
+		{"", &WrappedTx{tx: make([]byte, 5), priority: 25, height: 4}, []string{"tx--a"}},
+	}
+
+	for _, tc := range testCases {
+		policy, ok := lookupEvictionPolicy(tc.policy)
+		require.True(t, ok)
+		evictTxs := pq.GetEvictableTxs(policy, tc.incoming, totalSize, totalSize, 0)
+
+		var got []string
+		for _, wtx := range evictTxs {
+			got = append(got, string(wtx.tx))
+		}
+		require.Equal(t, tc.expected, got, "policy=%s incoming=%+v", tc.policy, tc.incoming)
+	}
+
+	// with an overhead per tx, evicting a tx frees its overhead too
+	policy, _ := lookupEvictionPolicy(config.EvictionPolicyPriority)
+	withOverhead := totalSize + int64(10*len(residents))
+	evictTxs := pq.GetEvictableTxs(policy, &WrappedTx{tx: make([]byte, 5), priority: 25}, withOverhead, withOverhead, 10)
+	require.Equal(t, []*WrappedTx{residents[0]}, evictTxs)
+}
+
 func TestTxPriorityQueue_RemoveTxEvm(t *testing.T) {
 	pq := NewTxPriorityQueue()
 
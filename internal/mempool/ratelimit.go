@@ -0,0 +1,183 @@
+package mempool
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/types"
+)
+
+// tokenBucket is a token bucket that refills at rate tokens per second up to
+// burst tokens. A bucket with a zero rate is disabled and always allows.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate, burst float64, now time.Time) tokenBucket {
+	return tokenBucket{rate: rate, burst: burst, tokens: burst, last: now}
+}
+
+// refill adds the tokens accrued since the last refill.
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+func (b *tokenBucket) has(n float64) bool {
+	return b.rate == 0 || b.tokens >= n
+}
+
+func (b *tokenBucket) take(n float64) {
+	if b.rate != 0 {
+		b.tokens -= n
+	}
+}
+
+// full reports whether the bucket holds as many tokens as a new one.
+func (b *tokenBucket) full() bool {
+	return b.rate == 0 || b.tokens >= b.burst
+}
+
+type peerBuckets struct {
+	txs   tokenBucket
+	bytes tokenBucket
+}
+
+func newPeerBuckets(txRate, bytesRate float64, now time.Time) *peerBuckets {
+	return &peerBuckets{
+		txs:   newTokenBucket(txRate, math.Max(1, math.Ceil(txRate)), now),
+		bytes: newTokenBucket(bytesRate, bytesRate, now),
+	}
+}
+
+// peerRateLimiter enforces the per-peer transaction and byte quotas defined by
+// PeerTxRateLimit and PeerBytesRateLimit, and the quotas shared by all RPC
+// submissions defined by RPCTxRateLimit and RPCBytesRateLimit. Each quota may
+// burst up to one second's worth of its rate.
+type peerRateLimiter struct {
+	mtx   sync.Mutex
+	peers map[types.NodeID]*peerBuckets
+	rpc   *peerBuckets
+
+	// disconnected holds the peers which were removed while their buckets
+	// were still refilling.
+	disconnected map[types.NodeID]struct{}
+
+	txRate    float64
+	bytesRate float64
+
+	now func() time.Time
+}
+
+// newPeerRateLimiter returns a limiter for the quotas in cfg, or nil if no
+// quota is configured.
+func newPeerRateLimiter(cfg *config.MempoolConfig) *peerRateLimiter {
+	if cfg.PeerTxRateLimit == 0 && cfg.PeerBytesRateLimit == 0 &&
+		cfg.RPCTxRateLimit == 0 && cfg.RPCBytesRateLimit == 0 {
+		return nil
+	}
+
+	now := time.Now
+	return &peerRateLimiter{
+		peers:        make(map[types.NodeID]*peerBuckets),
+		rpc:          newPeerBuckets(cfg.RPCTxRateLimit, float64(cfg.RPCBytesRateLimit), now()),
+		disconnected: make(map[types.NodeID]struct{}),
+		txRate:       cfg.PeerTxRateLimit,
+		bytesRate:    float64(cfg.PeerBytesRateLimit),
+		now:          now,
+	}
+}
+
+// allow charges a transaction of txSize bytes against the quotas of peerID.
+// It returns ErrRateLimited, without charging anything, if either quota is
+// exhausted. Transactions without a sending peer, e.g. replayed from the WAL
+// or checked for a proposed block, are never limited.
+func (l *peerRateLimiter) allow(peerID types.NodeID, txSize int) error {
+	if l == nil || peerID == "" {
+		return nil
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	pb, ok := l.peers[peerID]
+	if !ok {
+		pb = newPeerBuckets(l.txRate, l.bytesRate, now)
+		l.peers[peerID] = pb
+	}
+	return pb.charge(now, txSize, peerID)
+}
+
+// allowRPC charges a transaction of txSize bytes submitted via RPC against
+// the quotas shared by all RPC submissions, like allow.
+func (l *peerRateLimiter) allowRPC(txSize int) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.rpc.charge(l.now(), txSize, "")
+}
+
+// charge takes a transaction of txSize bytes from the buckets, or returns
+// ErrRateLimited for peerID if either of them is exhausted.
+func (pb *peerBuckets) charge(now time.Time, txSize int, peerID types.NodeID) error {
+	pb.txs.refill(now)
+	pb.bytes.refill(now)
+
+	if !pb.txs.has(1) {
+		return types.ErrRateLimited{PeerID: peerID, Quota: "txs"}
+	}
+	if !pb.bytes.has(float64(txSize)) {
+		return types.ErrRateLimited{PeerID: peerID, Quota: "bytes"}
+	}
+
+	pb.txs.take(1)
+	pb.bytes.take(float64(txSize))
+	return nil
+}
+
+// removePeer drops the quota state of peerID once its buckets refilled, so
+// that a peer can't reset its quotas by reconnecting. The state of peers
+// removed earlier is dropped as well once their buckets refilled.
+func (l *peerRateLimiter) removePeer(peerID types.NodeID) {
+	if l == nil {
+		return
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	l.disconnected[peerID] = struct{}{}
+	for id := range l.disconnected {
+		if pb, ok := l.peers[id]; ok {
+			pb.txs.refill(now)
+			pb.bytes.refill(now)
+			if !pb.txs.full() || !pb.bytes.full() {
+				continue
+			}
+		}
+		delete(l.peers, id)
+		delete(l.disconnected, id)
+	}
+}
+
+// rateLimitSource returns the source of a transaction reported by the
+// RateLimitedTxs metric.
+func rateLimitSource(txInfo TxInfo) string {
+	if txInfo.RPC {
+		return "rpc"
+	}
+	return "peer"
+}
@@ -0,0 +1,133 @@
+package mempool
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/types"
+)
+
+func newTestRateLimiter(txRate float64, bytesRate int64) (*peerRateLimiter, *time.Time) {
+	cfg := config.TestMempoolConfig()
+	cfg.PeerTxRateLimit = txRate
+	cfg.PeerBytesRateLimit = bytesRate
+
+	now := time.Now()
+	l := newPeerRateLimiter(cfg)
+	if l != nil {
+		l.now = func() time.Time { return now }
+	}
+	return l, &now
+}
+
+func requireRateLimited(t *testing.T, err error, quota string) {
+	t.Helper()
+
+	var rlErr types.ErrRateLimited
+	require.True(t, errors.As(err, &rlErr), "expected ErrRateLimited, got %v", err)
+	require.Equal(t, quota, rlErr.Quota)
+}
+
+func TestPeerRateLimiterDisabled(t *testing.T) {
+	l, _ := newTestRateLimiter(0, 0)
+	require.Nil(t, l)
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, l.allow("peer", 1024))
+	}
+	l.removePeer("peer")
+}
+
+func TestPeerRateLimiterTxs(t *testing.T) {
+	l, now := newTestRateLimiter(10, 0)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, l.allow("peer", 1024))
+	}
+	requireRateLimited(t, l.allow("peer", 1024), "txs")
+
+	// other peers, RPC and internal submissions have their own quota
+	require.NoError(t, l.allow("other", 1024))
+	require.NoError(t, l.allowRPC(1024))
+	require.NoError(t, l.allow("", 1024))
+
+	*now = now.Add(250 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, l.allow("peer", 1024))
+	}
+	requireRateLimited(t, l.allow("peer", 1024), "txs")
+
+	// the quota never exceeds the burst
+	*now = now.Add(time.Hour)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, l.allow("peer", 1024))
+	}
+	requireRateLimited(t, l.allow("peer", 1024), "txs")
+}
+
+func TestPeerRateLimiterBytes(t *testing.T) {
+	l, now := newTestRateLimiter(0, 1000)
+
+	require.NoError(t, l.allow("peer", 600))
+	requireRateLimited(t, l.allow("peer", 600), "bytes")
+
+	// a rejected tx is not charged
+	require.NoError(t, l.allow("peer", 400))
+	requireRateLimited(t, l.allow("peer", 1), "bytes")
+
+	*now = now.Add(500 * time.Millisecond)
+	require.NoError(t, l.allow("peer", 500))
+	requireRateLimited(t, l.allow("peer", 1), "bytes")
+}
+
+func TestPeerRateLimiterRPC(t *testing.T) {
+	cfg := config.TestMempoolConfig()
+	cfg.RPCTxRateLimit = 2
+	cfg.RPCBytesRateLimit = int64(cfg.MaxTxBytes)
+	l := newPeerRateLimiter(cfg)
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	// all RPC submissions share a quota
+	require.NoError(t, l.allowRPC(1))
+	require.NoError(t, l.allowRPC(1))
+	err := l.allowRPC(1)
+	requireRateLimited(t, err, "txs")
+	require.Contains(t, err.Error(), "rpc")
+
+	// peers are not limited
+	for i := 0; i < 10; i++ {
+		require.NoError(t, l.allow("peer", 1024))
+	}
+
+	now = now.Add(time.Second)
+	requireRateLimited(t, l.allowRPC(cfg.MaxTxBytes+1), "bytes")
+	require.NoError(t, l.allowRPC(cfg.MaxTxBytes))
+}
+
+func TestPeerRateLimiterRemovePeer(t *testing.T) {
+	l, now := newTestRateLimiter(1, 0)
+
+	require.NoError(t, l.allow("peer", 1))
+	requireRateLimited(t, l.allow("peer", 1), "txs")
+
+	// reconnecting does not reset an exhausted quota
+	l.removePeer("peer")
+	requireRateLimited(t, l.allow("peer", 1), "txs")
+
+	// the state is dropped once the buckets refilled
+	*now = now.Add(time.Second)
+	l.removePeer("other")
+	require.Empty(t, l.peers)
+	require.Empty(t, l.disconnected)
+	require.NoError(t, l.allow("peer", 1))
+
+	// a peer whose buckets are full is dropped right away
+	*now = now.Add(time.Second)
+	l.removePeer("peer")
+	require.Empty(t, l.peers)
+}
@@ -7,6 +7,9 @@ import (
 	"runtime/debug"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/internal/libs/clist"
 	"github.com/tendermint/tendermint/internal/p2p"
@@ -99,7 +102,8 @@ func GetChannelDescriptor(cfg *config.MempoolConfig) *p2p.ChannelDescriptor {
 
 // OnStart starts separate go routines for each p2p Channel and listens for
 // envelopes on each. In addition, it also listens for peer updates and handles
-// messages on that p2p channel accordingly. The caller must be sure to execute
+// messages on that p2p channel accordingly. If the mempool WAL is enabled, the
+// transactions it holds are replayed first. The caller must be sure to execute
 // OnStop to ensure the outbound p2p Channels are closed.
 func (r *Reactor) OnStart(ctx context.Context) error {
 	if !r.cfg.Broadcast {
@@ -109,6 +113,13 @@ func (r *Reactor) OnStart(ctx context.Context) error {
 	if r.channel == nil {
 		return errors.New("mempool channel is not set")
 	}
+
+	if r.cfg.WalEnabled() {
+		if err := r.mempool.InitWAL(ctx); err != nil {
+			return fmt.Errorf("initializing mempool wal: %w", err)
+		}
+	}
+	r.mempool.start(ctx)
 	go r.processMempoolCh(ctx, r.channel)
 	go r.processPeerUpdates(ctx, r.peerEvents(ctx), r.channel)
 
@@ -117,7 +128,9 @@ func (r *Reactor) OnStart(ctx context.Context) error {
 
 // OnStop stops the reactor by signaling to all spawned goroutines to exit and
 // blocking until they all exit.
-func (r *Reactor) OnStop() {}
+func (r *Reactor) OnStop() {
+	r.mempool.CloseWAL()
+}
 
 // handleMempoolMessage handles envelopes sent from peers on the MempoolChannel.
 // For every tx in the message, we execute CheckTx. It returns an error if an
@@ -133,6 +146,12 @@ func (r *Reactor) handleMempoolMessage(ctx context.Context, envelope *p2p.Envelo
 			return errors.New("empty txs received from peer")
 		}
 
+		ctx, span := r.mempool.tracer.Start(ctx, "mempool.Reactor.handleTxs", trace.WithAttributes(
+			attribute.String("peer", string(envelope.From)),
+			attribute.Int("txs", len(protoTxs)),
+		))
+		defer span.End()
+
 		txInfo := TxInfo{SenderID: r.ids.GetForPeer(envelope.From)}
 		if len(envelope.From) != 0 {
 			txInfo.SenderNodeID = envelope.From
@@ -158,6 +177,8 @@ func (r *Reactor) handleMempoolMessage(ctx context.Context, envelope *p2p.Envelo
 					return nil
 				}
 
+				// rate limited txs are counted by the RateLimitedTxs
+				// metric, with the "peer" source
 				logger.Debug("checktx failed for tx",
 					"tx", fmt.Sprintf("%X", types.Tx(tx).Hash()),
 					"err", err)
@@ -257,6 +278,7 @@ func (r *Reactor) processPeerUpdate(ctx context.Context, peerUpdate p2p.PeerUpda
 
 	case p2p.PeerStatusDown:
 		r.ids.Reclaim(peerUpdate.NodeID)
+		r.mempool.removePeer(peerUpdate.NodeID)
 
 		// Check if we've started a tx broadcasting goroutine for this peer.
 		// If we have, we signal to terminate the goroutine via the channel's closure.
@@ -326,7 +348,9 @@ func (r *Reactor) broadcastTxRoutine(ctx context.Context, peerID types.NodeID, m
 
 		// NOTE: Transaction batching was disabled due to:
 		// https://github.com/tendermint/tendermint/issues/5796
-		if ok := r.mempool.txStore.TxHasPeer(memTx.hash, peerMempoolID); !ok {
+		//
+		// Private transactions are never gossiped.
+		if ok := r.mempool.txStore.TxHasPeer(memTx.hash, peerMempoolID); !ok && !memTx.private {
 			// Send the mempool tx to the corresponding peer. Note, the peer may be
 			// behind and thus would not be able to process the mempool tx correctly.
 			if err := mempoolCh.Send(ctx, p2p.Envelope{
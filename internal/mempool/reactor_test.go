@@ -297,6 +297,33 @@ func TestReactorNoBroadcastToSender(t *testing.T) {
 	}, time.Minute, 100*time.Millisecond)
 }
 
+func TestReactorNoBroadcastPrivateTxs(t *testing.T) {
+	numNodes := 2
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	rts := setupReactors(ctx, t, logger, numNodes, 2)
+
+	primary := rts.nodes[0]
+	secondary := rts.nodes[1]
+
+	privateTx := types.Tx("sender-private=key=1")
+	require.NoError(t, rts.mempools[primary].CheckTx(ctx, privateTx, nil, TxInfo{Private: true}))
+	publicTx := types.Tx("sender-public=key=1")
+	require.NoError(t, rts.mempools[primary].CheckTx(ctx, publicTx, nil, TxInfo{}))
+	require.Equal(t, 2, rts.mempools[primary].Size())
+
+	rts.start(ctx, t)
+
+	// The public tx was added after the private one, so once it reached the
+	// secondary the private one must have been skipped.
+	rts.waitForTxns(t, []types.Tx{publicTx}, secondary)
+	require.Equal(t, 1, rts.mempools[secondary].Size())
+	require.False(t, rts.mempools[secondary].HasTx(privateTx.Key()))
+}
+
 func TestReactor_MaxTxBytes(t *testing.T) {
 	numNodes := 2
 	cfg := config.TestConfig()
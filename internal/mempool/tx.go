@@ -22,6 +22,15 @@ type TxInfo struct {
 
 	// SenderNodeID is the actual types.NodeID of the sender.
 	SenderNodeID types.NodeID
+
+	// Private marks the transaction as private: it is kept in the local
+	// mempool and may be included in blocks proposed by this node, but it is
+	// never gossiped to peers.
+	Private bool
+
+	// RPC marks the transaction as submitted via RPC, to charge it against
+	// the RPC rate limits.
+	RPC bool
 }
 
 // WrappedTx defines a wrapper around a raw transaction with additional metadata
@@ -67,6 +76,18 @@ type WrappedTx struct {
 	// a reCheckTx callback executed.
 	removed bool
 
+	// stale marks the transaction's CheckTx verdict as outdated because its
+	// re-CheckTx after the latest block could not be completed. Stale
+	// transactions are never reaped.
+	stale bool
+
+	// private marks the transaction as one that must not be gossiped to peers.
+	private bool
+
+	// senderID is the internal ID of the peer the transaction was received
+	// from, if any.
+	senderID uint16
+
 	// this is the callback that can be called when a transaction is removed
 	removeHandler func(removeFromCache bool)
 
@@ -6,6 +6,7 @@ import (
 	"math"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/internal/p2p"
 	"github.com/tendermint/tendermint/types"
 )
@@ -52,7 +53,8 @@ type Mempool interface {
 
 	// ReapMaxTxs reaps up to max transactions from the mempool. If max is
 	// negative, there is no cap on the size of all returned transactions
-	// (~ all available transactions).
+	// (~ all available transactions). Private transactions are never
+	// returned, as the result is exposed over RPC.
 	ReapMaxTxs(max int) types.Txs
 
 	// Lock locks the mempool. The consensus must be able to hold lock to safely
@@ -88,6 +90,28 @@ type Mempool interface {
 	// Flush removes all transactions from the mempool and caches.
 	Flush()
 
+	// ExportTxs returns the transactions in the mempool, in arrival order.
+	ExportTxs() types.Txs
+
+	// ForEachTx calls fn for each of the transactions ReapMaxTxs(max) returns,
+	// in the same order, until fn returns false. fn is called without holding
+	// the mempool's lock, so it may block.
+	ForEachTx(max int, fn func(types.Tx) bool)
+
+	// ForEachExportedTx calls fn for each of the transactions ExportTxs
+	// returns, in the same order, until fn returns false. fn is called without
+	// holding the mempool's lock, so it may block.
+	ForEachExportedTx(fn func(types.Tx) bool)
+
+	// ImportTxs executes CheckTx for each of txs and returns the number of
+	// transactions accepted.
+	ImportTxs(ctx context.Context, txs types.Txs) (int, error)
+
+	// UpdateConfig applies update to a copy of the mempool config and swaps
+	// it in if the result is valid and only changes settings which take effect
+	// at runtime.
+	UpdateConfig(update func(*config.MempoolConfig)) error
+
 	// TxsAvailable returns a channel which fires once for every height, and only
 	// when transactions are available in the mempool.
 	//
@@ -105,6 +129,15 @@ type Mempool interface {
 	// SizeBytes returns the total size of all txs in the mempool.
 	SizeBytes() int64
 
+	// IsDegraded returns true if some transactions could not be rechecked
+	// against the application after the latest block, even once retried for
+	// recheck-retry-timeout. Such transactions are excluded from reaping.
+	IsDegraded() bool
+
+	// CheckTxBreakerState returns the state of the CheckTx circuit breaker:
+	// "closed", "open" or "half-open". A disabled breaker is always closed.
+	CheckTxBreakerState() string
+
 	TxStore() *TxStore
 }
 
@@ -0,0 +1,416 @@
+package mempool
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/tendermint/tendermint/internal/libs/protoio"
+	protomem "github.com/tendermint/tendermint/proto/tendermint/mempool"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	walFileName = "wal"
+
+	// walCompactionRatio is the size of the log, relative to the size of the
+	// transactions it must keep, past which it is compacted.
+	walCompactionRatio = 2
+
+	// walMinCompactionBytes is the size below which the log is never
+	// compacted after a block.
+	walMinCompactionBytes = 1024 * 1024 // 1MB
+)
+
+// mempoolWAL is an append-only log of the transactions admitted to and
+// removed from the mempool. Each admission or removal is written as a
+// length-delimited WALRecord with a single write, so a crash can at most leave
+// a truncated last record.
+//
+// Removals only append a small record, so the log grows until it is
+// compacted, i.e. rewritten in the background to hold a snapshot of the
+// mempool.
+//
+// The log is not synced on every append: it survives a crash of the process,
+// but not necessarily of the host.
+type mempoolWAL struct {
+	mtx  sync.Mutex
+	path string
+	file *os.File
+	size int64
+
+	minCompactionBytes int64
+
+	// While a compaction runs, the records written after its snapshot was
+	// taken are kept in appended, so that they are carried over to the
+	// compacted log.
+	compacting bool
+	appended   [][]byte
+	wg         sync.WaitGroup
+}
+
+// readWAL returns the transactions recorded in the log at path that were not
+// removed since, and the number of records it had to skip. A missing log holds
+// no transactions. The log is only a recovery aid, so it never fails on its
+// content: a record larger than maxTxBytes allows, e.g. after max-tx-bytes was
+// lowered, or one that does not decode is skipped, and a truncated record or
+// a corrupt length ends the log.
+func readWAL(path string, maxTxBytes int) (types.Txs, int, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	} else if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var (
+		r = bufio.NewReader(f)
+		// leave room for the field tag and length of the single tx
+		maxRecordBytes = uint64(maxTxBytes + 16)
+
+		txs     types.Txs
+		skipped int
+		indexes = make(map[types.TxKey]int)
+	)
+	for {
+		length, err := binary.ReadUvarint(r)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		} else if err != nil || length > math.MaxInt32 {
+			// the following records can't be delimited
+			skipped++
+			break
+		}
+
+		if length > maxRecordBytes {
+			skipped++
+			if _, err := io.CopyN(io.Discard, r, int64(length)); err != nil {
+				break
+			}
+			continue
+		}
+
+		bz := make([]byte, length)
+		if _, err := io.ReadFull(r, bz); err != nil {
+			break
+		}
+
+		var record protomem.WALRecord
+		if err := record.Unmarshal(bz); err != nil {
+			skipped++
+			continue
+		}
+
+		switch sum := record.Sum.(type) {
+		case *protomem.WALRecord_Tx:
+			tx := types.Tx(sum.Tx)
+			if _, ok := indexes[tx.Key()]; !ok {
+				indexes[tx.Key()] = len(txs)
+				txs = append(txs, tx)
+			}
+
+		case *protomem.WALRecord_Removed:
+			var key types.TxKey
+			copy(key[:], sum.Removed)
+			if i, ok := indexes[key]; ok {
+				txs[i] = nil
+				delete(indexes, key)
+			}
+
+		default:
+			skipped++
+		}
+	}
+	return liveTxs(txs), skipped, nil
+}
+
+// liveTxs drops the removed (nil) transactions from txs.
+func liveTxs(txs types.Txs) types.Txs {
+	live := txs[:0]
+	for _, tx := range txs {
+		if tx != nil {
+			live = append(live, tx)
+		}
+	}
+	return live
+}
+
+func openWAL(path string) (*mempoolWAL, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &mempoolWAL{
+		path:               path,
+		file:               f,
+		size:               info.Size(),
+		minCompactionBytes: walMinCompactionBytes,
+	}, nil
+}
+
+func encodeWALRecord(tx types.Tx) ([]byte, error) {
+	return protoio.MarshalDelimited(&protomem.WALRecord{Sum: &protomem.WALRecord_Tx{Tx: tx}})
+}
+
+func encodeWALRemoval(key types.TxKey) ([]byte, error) {
+	return protoio.MarshalDelimited(&protomem.WALRecord{Sum: &protomem.WALRecord_Removed{Removed: key[:]}})
+}
+
+// write appends tx to the log.
+func (w *mempoolWAL) write(tx types.Tx) error {
+	if w == nil {
+		return nil
+	}
+
+	bz, err := encodeWALRecord(tx)
+	if err != nil {
+		return err
+	}
+	return w.append(bz)
+}
+
+// writeRemoved appends the removal of the transaction with key to the log.
+func (w *mempoolWAL) writeRemoved(key types.TxKey) error {
+	if w == nil {
+		return nil
+	}
+
+	bz, err := encodeWALRemoval(key)
+	if err != nil {
+		return err
+	}
+	return w.append(bz)
+}
+
+func (w *mempoolWAL) append(bz []byte) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	n, err := w.file.Write(bz)
+	w.size += int64(n)
+	if w.compacting {
+		w.appended = append(w.appended, bz)
+	}
+	return err
+}
+
+// needsCompaction reports whether the log grew past walCompactionRatio times
+// liveBytes, the size of the transactions it must keep.
+func (w *mempoolWAL) needsCompaction(liveBytes int64) bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	return !w.compacting && w.size > w.minCompactionBytes && w.size > walCompactionRatio*liveBytes
+}
+
+// compact rewrites the log in the background, see rewrite. It is a no-op if a
+// compaction is already running.
+func (w *mempoolWAL) compact(txs types.Txs, onError func(error)) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.compacting {
+		return
+	}
+	w.compacting = true
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		if err := w.rewrite(txs); err != nil {
+			onError(err)
+		}
+	}()
+}
+
+// rewrite atomically replaces the content of the log with txs, followed by
+// the records written since the compaction began. The bulk of the log is
+// written without holding the log's lock, so appends are not blocked.
+func (w *mempoolWAL) rewrite(txs types.Txs) error {
+	if w == nil {
+		return nil
+	}
+
+	w.mtx.Lock()
+	w.compacting = true
+	w.mtx.Unlock()
+
+	var (
+		tmpPath = w.path + ".tmp"
+		size    int64
+	)
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err == nil {
+		size, err = writeWALRecords(tmp, txs)
+		if err == nil {
+			err = tmp.Sync()
+		}
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	defer func() {
+		w.compacting = false
+		w.appended = nil
+	}()
+
+	if tmp == nil {
+		return err
+	}
+	for _, bz := range w.appended {
+		if err != nil {
+			break
+		}
+		var n int
+		n, err = tmp.Write(bz)
+		size += int64(n)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, w.path); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w.file.Close()
+	w.file = f
+	w.size = size
+	return nil
+}
+
+// writeWALRecords writes a record for each of txs to f and returns the number
+// of bytes written.
+func writeWALRecords(f *os.File, txs types.Txs) (int64, error) {
+	var size int64
+	for _, tx := range txs {
+		bz, err := encodeWALRecord(tx)
+		if err != nil {
+			return size, err
+		}
+		n, err := f.Write(bz)
+		size += int64(n)
+		if err != nil {
+			return size, err
+		}
+	}
+	return size, nil
+}
+
+// close waits for a running compaction to finish, then closes the log.
+func (w *mempoolWAL) close() error {
+	if w == nil {
+		return nil
+	}
+
+	w.wg.Wait()
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.file.Close()
+}
+
+// InitWAL replays the transactions recorded in the mempool's write-ahead log
+// through CheckTx, then opens the log so that every transaction admitted from
+// now on is persisted. It must be called before the mempool receives any
+// transactions, and only if the WAL is enabled in the mempool config.
+func (txmp *TxMempool) InitWAL(ctx context.Context) error {
+	path := filepath.Join(txmp.config.WalDir(), walFileName)
+
+	txs, skipped, err := readWAL(path, txmp.config.MaxTxBytes)
+	if err != nil {
+		return err
+	}
+	if skipped > 0 {
+		txmp.logger.Error("skipped invalid records of mempool wal", "path", path, "skipped", skipped)
+	}
+
+	if _, err := txmp.ImportTxs(ctx, txs); err != nil {
+		return err
+	}
+
+	wal, err := openWAL(path)
+	if err != nil {
+		return err
+	}
+
+	txmp.Lock()
+	defer txmp.Unlock()
+
+	txmp.wal = wal
+	if err := wal.rewrite(txmp.exportTxs()); err != nil {
+		txmp.logger.Error("failed to compact mempool wal", "err", err)
+	}
+	txmp.logger.Info("replayed mempool wal", "path", path, "txs", len(txs), "size", txmp.Size())
+	return nil
+}
+
+// CloseWAL closes the mempool's write-ahead log, if it is open.
+func (txmp *TxMempool) CloseWAL() {
+	txmp.Lock()
+	defer txmp.Unlock()
+
+	if err := txmp.wal.close(); err != nil {
+		txmp.logger.Error("failed to close mempool wal", "err", err)
+	}
+	txmp.wal = nil
+}
+
+// walRemoved records the removal of wtx in the write-ahead log. Private
+// transactions are never logged, so their removal is not either.
+func (txmp *TxMempool) walRemoved(wtx *WrappedTx) {
+	if wtx.private {
+		return
+	}
+	if err := txmp.wal.writeRemoved(wtx.hash); err != nil {
+		txmp.logger.Error("failed to write tx removal to mempool wal", "err", err)
+	}
+}
+
+// compactWAL starts rewriting the write-ahead log in the background to hold
+// the transactions returned by ExportTxs, once the log grew past
+// walCompactionRatio times their size. Only the snapshot of the transactions
+// is taken under the lock.
+//
+// NOTE:
+// - The caller must have a write-lock when executing compactWAL.
+func (txmp *TxMempool) compactWAL() {
+	if txmp.wal == nil {
+		return
+	}
+
+	liveBytes := txmp.SizeBytes() + atomic.LoadInt64(&txmp.pendingSizeBytes)
+	if !txmp.wal.needsCompaction(liveBytes) {
+		return
+	}
+
+	txmp.wal.compact(txmp.exportTxs(), func(err error) {
+		txmp.logger.Error("failed to compact mempool wal", "err", err)
+	})
+}
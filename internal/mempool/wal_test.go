@@ -0,0 +1,234 @@
+package mempool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abciclient "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+func setupWithWAL(ctx context.Context, t *testing.T, walDir string) *TxMempool {
+	t.Helper()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	require.NoError(t, client.Start(ctx))
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 1000)
+	txmp.config.WalPath = walDir
+	require.NoError(t, txmp.InitWAL(ctx))
+	t.Cleanup(txmp.CloseWAL)
+	return txmp
+}
+
+func TestTxMempool_WALReplay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	walDir := t.TempDir()
+	txmp := setupWithWAL(ctx, t, walDir)
+
+	txs := convertTex(checkTxs(ctx, t, txmp, 100, 0))
+	privateTx := types.Tx("sender-private=key=1")
+	require.NoError(t, txmp.CheckTx(ctx, privateTx, nil, TxInfo{Private: true}))
+	require.Equal(t, 101, txmp.Size())
+
+	// commit the first half of the txs
+	responses := make([]*abci.ExecTxResult, 50)
+	for i := range responses {
+		responses[i] = &abci.ExecTxResult{Code: abci.CodeTypeOK}
+	}
+	txmp.Lock()
+	require.NoError(t, txmp.Update(ctx, 1, txs[:50], responses, nil, nil, true))
+	txmp.Unlock()
+	require.Equal(t, 51, txmp.Size())
+
+	// txs admitted after the block are appended to the log
+	newTx := types.Tx("sender-new=key=1")
+	require.NoError(t, txmp.CheckTx(ctx, newTx, nil, TxInfo{}))
+
+	// simulate a crash that left a truncated record at the end of the log
+	txmp.CloseWAL()
+	path := filepath.Join(walDir, walFileName)
+	bz, err := encodeWALRecord(types.Tx("sender-truncated=key=1"))
+	require.NoError(t, err)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.Write(bz[:len(bz)-3])
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	restarted := setupWithWAL(ctx, t, walDir)
+	require.Equal(t, 51, restarted.Size())
+	for _, tx := range append(txs[50:], newTx) {
+		require.True(t, restarted.HasTx(tx.Key()), "tx %X not replayed", tx.Hash())
+	}
+	require.False(t, restarted.HasTx(privateTx.Key()))
+
+	// the replayed log was compacted and no longer holds the truncated record
+	logged, _, err := readWAL(path, restarted.config.MaxTxBytes)
+	require.NoError(t, err)
+	require.ElementsMatch(t, append(txs[50:], newTx), logged)
+}
+
+func TestTxMempool_WALInvalidRecords(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	walDir := t.TempDir()
+	path := filepath.Join(walDir, walFileName)
+	first := types.Tx("sender-0=key=1")
+	last := types.Tx("sender-1=key=1")
+
+	// a tx larger than the current max-tx-bytes, e.g. because it was lowered
+	// since it was logged, and a corrupt record in the middle of the log
+	var raw []byte
+	for _, tx := range []types.Tx{first, make(types.Tx, config.TestMempoolConfig().MaxTxBytes+100)} {
+		bz, err := encodeWALRecord(tx)
+		require.NoError(t, err)
+		raw = append(raw, bz...)
+	}
+	raw = append(raw, 3, 0x0a, 0x05, 'a')
+	bz, err := encodeWALRecord(last)
+	require.NoError(t, err)
+	raw = append(raw, bz...)
+	require.NoError(t, os.WriteFile(path, raw, 0600))
+
+	logged, skipped, err := readWAL(path, config.TestMempoolConfig().MaxTxBytes)
+	require.NoError(t, err)
+	require.Equal(t, 2, skipped)
+	require.Equal(t, types.Txs{first, last}, logged)
+
+	// the node still starts, with the txs around the invalid records, and the
+	// log is compacted
+	txmp := setupWithWAL(ctx, t, walDir)
+	require.Equal(t, 2, txmp.Size())
+	logged, skipped, err = readWAL(path, txmp.config.MaxTxBytes)
+	require.NoError(t, err)
+	require.Zero(t, skipped)
+	require.ElementsMatch(t, types.Txs{first, last}, logged)
+}
+
+func TestTxMempool_WALDisabled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	require.NoError(t, client.Start(ctx))
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 1000)
+	require.False(t, txmp.config.WalEnabled())
+
+	_ = checkTxs(ctx, t, txmp, 10, 0)
+	txmp.Lock()
+	require.NoError(t, txmp.Update(ctx, 1, nil, nil, nil, nil, true))
+	txmp.Unlock()
+	txmp.CloseWAL()
+	require.Equal(t, 10, txmp.Size())
+}
+
+func TestMempoolWAL_CompactKeepsConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), walFileName)
+	wal, err := openWAL(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = wal.close() })
+
+	snapshot := make(types.Txs, 100)
+	for i := range snapshot {
+		snapshot[i] = types.Tx(fmt.Sprintf("sender-%d=key=1", i))
+		require.NoError(t, wal.write(snapshot[i]))
+	}
+	removed := types.Tx("sender-removed=key=1")
+	require.NoError(t, wal.write(removed))
+
+	// txs written and removed while the log is compacted are kept
+	wal.compact(snapshot, func(err error) { t.Error(err) })
+	written := types.Tx("sender-new=key=1")
+	require.NoError(t, wal.write(written))
+	require.NoError(t, wal.writeRemoved(snapshot[0].Key()))
+	wal.wg.Wait()
+
+	logged, _, err := readWAL(path, 1024)
+	require.NoError(t, err)
+	require.ElementsMatch(t, append(snapshot[1:], written), logged)
+}
+
+func TestMempoolWAL_Removed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), walFileName)
+	wal, err := openWAL(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = wal.close() })
+
+	txs := types.Txs{types.Tx("sender-0=key=1"), types.Tx("sender-1=key=1"), types.Tx("sender-2=key=1")}
+	for _, tx := range txs {
+		require.NoError(t, wal.write(tx))
+	}
+	require.NoError(t, wal.writeRemoved(txs[1].Key()))
+
+	logged, _, err := readWAL(path, 1024)
+	require.NoError(t, err)
+	require.Equal(t, types.Txs{txs[0], txs[2]}, logged)
+
+	// a removed tx written again is live again
+	require.NoError(t, wal.write(txs[1]))
+	logged, _, err = readWAL(path, 1024)
+	require.NoError(t, err)
+	require.Equal(t, types.Txs{txs[0], txs[2], txs[1]}, logged)
+}
+
+func TestTxMempool_WALCompactionThreshold(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	walDir := t.TempDir()
+	txmp := setupWithWAL(ctx, t, walDir)
+	txmp.wal.minCompactionBytes = 0
+	path := filepath.Join(walDir, walFileName)
+
+	txs := convertTex(checkTxs(ctx, t, txmp, 100, 0))
+	commit := func(height int64, txs types.Txs) {
+		t.Helper()
+		responses := make([]*abci.ExecTxResult, len(txs))
+		for i := range responses {
+			responses[i] = &abci.ExecTxResult{Code: abci.CodeTypeOK}
+		}
+		txmp.Lock()
+		require.NoError(t, txmp.Update(ctx, height, txs, responses, nil, nil, true))
+		txmp.Unlock()
+		txmp.wal.wg.Wait()
+	}
+
+	walSize := func() int64 {
+		t.Helper()
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		return info.Size()
+	}
+
+	// the log is not compacted while most of it is still live
+	size := walSize()
+	commit(1, txs[:10])
+	require.Greater(t, walSize(), size)
+	logged, _, err := readWAL(path, txmp.config.MaxTxBytes)
+	require.NoError(t, err)
+	require.ElementsMatch(t, txs[10:], logged)
+
+	// it is once removed txs make up most of it
+	size = walSize()
+	commit(2, txs[10:60])
+	require.Less(t, walSize(), size)
+	logged, _, err = readWAL(path, txmp.config.MaxTxBytes)
+	require.NoError(t, err)
+	require.ElementsMatch(t, txs[60:], logged)
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"fmt"
 
 	tmmath "github.com/tendermint/tendermint/libs/math"
 	"github.com/tendermint/tendermint/rpc/coretypes"
@@ -45,6 +46,47 @@ func (env *Environment) Validators(ctx context.Context, req *coretypes.RequestVa
 	}, nil
 }
 
+// NextProposers returns the validators expected to propose the next blocks,
+// starting at the latest uncommitted height. The proposers are computed for
+// round 0 and from the validator sets known to the node: beyond the next
+// height they assume the validator set does not change, so they are only a
+// prediction.
+func (env *Environment) NextProposers(ctx context.Context, req *coretypes.RequestNextProposers) (*coretypes.ResultNextProposers, error) {
+	count := defaultNextProposers
+	if req.Count != nil {
+		count = int(*req.Count)
+	}
+	if count < 1 || count > maxNextProposers {
+		return nil, fmt.Errorf("count must be between 1 and %d, got %d", maxNextProposers, count)
+	}
+
+	height := env.latestUncommittedHeight()
+	vals, err := env.StateStore.LoadValidators(height)
+	if err != nil {
+		return nil, err
+	}
+
+	proposers := make([]coretypes.Proposer, 0, count)
+	for i := int64(0); i < int64(count); i++ {
+		proposers = append(proposers, coretypes.Proposer{
+			Height:  height + i,
+			Address: vals.GetProposer().Address,
+		})
+
+		// the validator set of the next height is already stored, the later
+		// ones are extrapolated from it
+		if i == 0 {
+			if next, err := env.StateStore.LoadValidators(height + 1); err == nil {
+				vals = next
+				continue
+			}
+		}
+		vals = vals.CopyIncrementProposerPriority(1)
+	}
+
+	return &coretypes.ResultNextProposers{Proposers: proposers}, nil
+}
+
 // DumpConsensusState dumps consensus state.
 // UNSTABLE
 // More: https://docs.tendermint.com/master/rpc/#/Info/dump_consensus_state
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/state/mocks"
+	"github.com/tendermint/tendermint/rpc/coretypes"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestNextProposers(t *testing.T) {
+	ctx := context.Background()
+
+	vals, _ := types.RandValidatorSet(4, 10)
+	state := sm.State{
+		InitialHeight:               1,
+		Validators:                  vals,
+		NextValidators:              vals.CopyIncrementProposerPriority(1),
+		LastHeightValidatorsChanged: 1,
+	}
+
+	env := &Environment{}
+	env.StateStore = sm.NewStore(dbm.NewMemDB())
+	require.NoError(t, env.StateStore.Save(state))
+	mockstore := &mocks.BlockStore{}
+	mockstore.On("Height").Return(int64(0))
+	env.BlockStore = mockstore
+
+	count := coretypes.Int64(8)
+	res, err := env.NextProposers(ctx, &coretypes.RequestNextProposers{Count: &count})
+	require.NoError(t, err)
+	require.Len(t, res.Proposers, 8)
+
+	expected := vals
+	for i, proposer := range res.Proposers {
+		require.Equal(t, int64(i+1), proposer.Height)
+		require.Equal(t, expected.GetProposer().Address, proposer.Address, "height %d", proposer.Height)
+		expected = expected.CopyIncrementProposerPriority(1)
+	}
+
+	res, err = env.NextProposers(ctx, &coretypes.RequestNextProposers{})
+	require.NoError(t, err)
+	require.Len(t, res.Proposers, defaultNextProposers)
+
+	for _, c := range []coretypes.Int64{0, -1, maxNextProposers + 1} {
+		c := c
+		_, err := env.NextProposers(ctx, &coretypes.RequestNextProposers{Count: &c})
+		require.Error(t, err, "count %d", c)
+	}
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 
+	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/rpc/coretypes"
 )
 
@@ -11,3 +12,68 @@ func (env *Environment) UnsafeFlushMempool(ctx context.Context) (*coretypes.Resu
 	env.Mempool.Flush()
 	return &coretypes.ResultUnsafeFlushMempool{}, nil
 }
+
+// UnsafeDumpMempool returns the transactions in the mempool, including the
+// pending ones, in arrival order, so they can be loaded into another node with
+// UnsafeLoadMempool.
+func (env *Environment) UnsafeDumpMempool(ctx context.Context) (*coretypes.ResultUnsafeDumpMempool, error) {
+	return &coretypes.ResultUnsafeDumpMempool{Txs: env.Mempool.ExportTxs()}, nil
+}
+
+// UnsafeLoadMempool executes CheckTx for each of the given transactions and
+// adds the accepted ones to the mempool.
+func (env *Environment) UnsafeLoadMempool(
+	ctx context.Context,
+	req *coretypes.RequestUnsafeLoadMempool,
+) (*coretypes.ResultUnsafeLoadMempool, error) {
+	accepted, err := env.Mempool.ImportTxs(ctx, req.Txs)
+	if err != nil {
+		return nil, err
+	}
+	return &coretypes.ResultUnsafeLoadMempool{
+		Total:    len(req.Txs),
+		Accepted: accepted,
+	}, nil
+}
+
+// UnsafeUpdateMempoolConfig updates the mempool's size and rate limits without
+// restarting the node. Parameters that are not set keep their current value.
+// The new config is validated before it is applied.
+func (env *Environment) UnsafeUpdateMempoolConfig(
+	ctx context.Context,
+	req *coretypes.RequestUnsafeUpdateMempoolConfig,
+) (*coretypes.ResultUnsafeUpdateMempoolConfig, error) {
+	err := env.Mempool.UpdateConfig(func(cfg *config.MempoolConfig) {
+		if req.Size != nil {
+			cfg.Size = int(*req.Size)
+		}
+		if req.MaxTxsBytes != nil {
+			cfg.MaxTxsBytes = int64(*req.MaxTxsBytes)
+		}
+		if req.PendingSize != nil {
+			cfg.PendingSize = int(*req.PendingSize)
+		}
+		if req.MaxPendingTxsBytes != nil {
+			cfg.MaxPendingTxsBytes = int64(*req.MaxPendingTxsBytes)
+		}
+		if req.PeerTxRateLimit != nil {
+			cfg.PeerTxRateLimit = *req.PeerTxRateLimit
+		}
+		if req.PeerBytesRateLimit != nil {
+			cfg.PeerBytesRateLimit = int64(*req.PeerBytesRateLimit)
+		}
+		if req.RPCTxRateLimit != nil {
+			cfg.RPCTxRateLimit = *req.RPCTxRateLimit
+		}
+		if req.RPCBytesRateLimit != nil {
+			cfg.RPCBytesRateLimit = int64(*req.RPCBytesRateLimit)
+		}
+		if req.EvictionPolicy != nil {
+			cfg.EvictionPolicy = *req.EvictionPolicy
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &coretypes.ResultUnsafeUpdateMempoolConfig{}, nil
+}
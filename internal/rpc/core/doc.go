@@ -20,11 +20,13 @@ Available endpoints:
 /dump_consensus_state
 /genesis
 /net_info
+/next_proposers
 /num_unconfirmed_txs
 /status
 /lag_status
 /health
 /unconfirmed_txs
+/unsafe_dump_mempool
 /unsafe_flush_mempool
 /validators
 
@@ -40,6 +42,8 @@ Endpoints that require arguments:
 /dial_persistent_peers?persistent_peers=_
 /subscribe?event=_
 /tx?hash=_&prove=_
+/unsafe_load_mempool?txs=_
+/unsafe_update_mempool_config?size=_&max_txs_bytes=_&pending_size=_&max_pending_txs_bytes=_&peer_tx_rate_limit=_&peer_bytes_rate_limit=_&rpc_tx_rate_limit=_&rpc_bytes_rate_limit=_&eviction_policy=_
 /unsubscribe?event=_
 ```
 */
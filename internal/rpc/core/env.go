@@ -37,6 +37,10 @@ const (
 	defaultPerPage = 30
 	maxPerPage     = 100
 
+	// see NextProposers
+	defaultNextProposers = 10
+	maxNextProposers     = 100
+
 	// SubscribeTimeout is the maximum time we wait to subscribe for an event.
 	// must be less than the server's write timeout (see rpcserver.DefaultConfig)
 	SubscribeTimeout = 5 * time.Second
@@ -315,14 +319,14 @@ func (env *Environment) StartService(ctx context.Context, conf *config.Config) (
 			return nil, err
 		}
 
-		var rootHandler http.Handler = mux
+		var rootHandler http.Handler = env.withProtoEncoding(mux)
 		if conf.RPC.IsCorsEnabled() {
 			corsMiddleware := cors.New(cors.Options{
 				AllowedOrigins: conf.RPC.CORSAllowedOrigins,
 				AllowedMethods: conf.RPC.CORSAllowedMethods,
 				AllowedHeaders: conf.RPC.CORSAllowedHeaders,
 			})
-			rootHandler = corsMiddleware.Handler(mux)
+			rootHandler = corsMiddleware.Handler(rootHandler)
 		}
 		if conf.RPC.IsTLSEnabled() {
 			go func() {
@@ -6,9 +6,13 @@ import (
 	"github.com/tendermint/tendermint/rpc/coretypes"
 )
 
-// Health gets node health. Returns empty result (200 OK) on success, no
-// response - in case of an error.
+// Health gets node health. Returns the state of the node's mempool (200 OK) on
+// success, no response - in case of an error.
 // More: https://docs.tendermint.com/master/rpc/#/Info/health
 func (env *Environment) Health(ctx context.Context) (*coretypes.ResultHealth, error) {
-	return &coretypes.ResultHealth{}, nil
+	result := &coretypes.ResultHealth{}
+	if env.Mempool != nil {
+		result.Mempool = env.mempoolInfo()
+	}
+	return result, nil
 }
@@ -0,0 +1,27 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/internal/mempool/mocks"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/rpc/coretypes"
+)
+
+func TestHealthReportsMempool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mp := &mocks.Mempool{}
+	mp.On("IsDegraded").Return(true)
+	mp.On("CheckTxBreakerState").Return("half-open")
+	env := &Environment{Mempool: mp, Logger: log.NewNopLogger()}
+
+	res, err := env.Health(ctx)
+	require.NoError(t, err)
+	require.Equal(t, coretypes.MempoolInfo{Degraded: true, CheckTxBreaker: "half-open"}, res.Mempool)
+	mp.AssertExpectations(t)
+}
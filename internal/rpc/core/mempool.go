@@ -1,17 +1,28 @@
 package core
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/internal/libs/protoio"
 	"github.com/tendermint/tendermint/internal/mempool"
 	"github.com/tendermint/tendermint/internal/state/indexer"
 	tmmath "github.com/tendermint/tendermint/libs/math"
+	protomempool "github.com/tendermint/tendermint/proto/tendermint/mempool"
 	"github.com/tendermint/tendermint/rpc/coretypes"
+	"github.com/tendermint/tendermint/types"
 )
 
 //-----------------------------------------------------------------------------
@@ -23,7 +34,7 @@ import (
 // https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_async
 // Deprecated and should be removed in 0.37
 func (env *Environment) BroadcastTxAsync(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error) {
-	go func() { _ = env.Mempool.CheckTx(ctx, req.Tx, nil, mempool.TxInfo{}) }()
+	go func() { _ = env.Mempool.CheckTx(ctx, req.Tx, nil, mempool.TxInfo{RPC: true}) }()
 
 	return &coretypes.ResultBroadcastTx{Hash: req.Tx.Hash()}, nil
 }
@@ -37,6 +48,18 @@ func (env *Environment) BroadcastTxSync(ctx context.Context, req *coretypes.Requ
 // DeliverTx result.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_sync
 func (env *Environment) BroadcastTx(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error) {
+	return env.broadcastTx(ctx, req, mempool.TxInfo{RPC: true})
+}
+
+// BroadcastTxPrivate returns with the response from CheckTx, like
+// BroadcastTx, but the transaction is only kept in this node's mempool and is
+// never gossiped to peers. It is only included in blocks proposed by this
+// node.
+func (env *Environment) BroadcastTxPrivate(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error) {
+	return env.broadcastTx(ctx, req, mempool.TxInfo{Private: true, RPC: true})
+}
+
+func (env *Environment) broadcastTx(ctx context.Context, req *coretypes.RequestBroadcastTx, txInfo mempool.TxInfo) (*coretypes.ResultBroadcastTx, error) {
 	resCh := make(chan *abci.ResponseCheckTx, 1)
 	err := env.Mempool.CheckTx(
 		ctx,
@@ -47,7 +70,7 @@ func (env *Environment) BroadcastTx(ctx context.Context, req *coretypes.RequestB
 			case resCh <- res:
 			}
 		},
-		mempool.TxInfo{},
+		txInfo,
 	)
 	if err != nil {
 		return nil, err
@@ -80,7 +103,7 @@ func (env *Environment) BroadcastTxCommit(ctx context.Context, req *coretypes.Re
 			case resCh <- res:
 			}
 		},
-		mempool.TxInfo{},
+		mempool.TxInfo{RPC: true},
 	)
 	if err != nil {
 		return nil, err
@@ -171,6 +194,189 @@ func (env *Environment) UnconfirmedTxs(ctx context.Context, req *coretypes.Reque
 	}, nil
 }
 
+// unconfirmedTxsChunkSize is the maximum number of transactions written in a
+// single Txs message of a binary (proto) response.
+const unconfirmedTxsChunkSize = 1024
+
+// wantsProtoEncoding reports whether the client asked for a binary (proto)
+// encoded response instead of the default JSON one.
+func wantsProtoEncoding(req *http.Request) bool {
+	if req.URL.Query().Get("encoding") == "proto" {
+		return true
+	}
+	for _, accept := range req.Header.Values("Accept") {
+		for _, mediaType := range strings.Split(accept, ",") {
+			if i := strings.IndexByte(mediaType, ';'); i >= 0 {
+				mediaType = mediaType[:i]
+			}
+			if strings.TrimSpace(mediaType) == coretypes.ProtoContentType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// withProtoEncoding wraps the RPC handler so that GET requests on the
+// unconfirmed_txs endpoint, and on the unsafe_dump_mempool one if unsafe
+// routes are enabled, that negotiate proto encoding are answered with a stream
+// of length-prefixed protobuf messages (see writeUnconfirmedTxsProto and
+// writeDumpMempoolProto). All other requests are passed to next unchanged.
+func (env *Environment) withProtoEncoding(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var write func(io.Writer) error
+		switch {
+		case !wantsProtoEncoding(req):
+		case req.URL.Path == "/unconfirmed_txs":
+			unconfirmedReq, err := parseUnconfirmedTxsQuery(req.URL.Query())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			perPage := env.validatePerPage(unconfirmedReq.PerPage.IntPtr())
+			page, err := validatePage(unconfirmedReq.Page.IntPtr(), perPage, env.Mempool.Size())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			write = func(w io.Writer) error { return env.writeUnconfirmedTxsProto(w, page, perPage) }
+		case req.URL.Path == "/unsafe_dump_mempool" && env.Config.Unsafe:
+			write = env.writeDumpMempoolProto
+		}
+		if write == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		w.Header().Set("Content-Type", coretypes.ProtoContentType)
+		w.WriteHeader(http.StatusOK)
+		if err := write(w); err != nil {
+			env.Logger.Error("failed to write binary response", "path", req.URL.Path, "err", err)
+		}
+	})
+}
+
+// parseUnconfirmedTxsQuery parses the page and per_page parameters of an
+// unconfirmed_txs GET request.
+func parseUnconfirmedTxsQuery(query url.Values) (*coretypes.RequestUnconfirmedTxs, error) {
+	var req coretypes.RequestUnconfirmedTxs
+	for name, dst := range map[string]**coretypes.Int64{
+		"page":     &req.Page,
+		"per_page": &req.PerPage,
+	} {
+		if v := query.Get(name); v != "" {
+			z, err := strconv.ParseInt(strings.Trim(v, `"`), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s parameter: %w", name, err)
+			}
+			i := coretypes.Int64(z)
+			*dst = &i
+		}
+	}
+	return &req, nil
+}
+
+// writeUnconfirmedTxsProto writes the given page of unconfirmed transactions
+// to w as a sequence of varint length-prefixed protobuf messages: an
+// UnconfirmedTxsHeader followed by a Txs stream (see protoTxsWriter). The
+// transactions are encoded one chunk at a time while iterating the mempool, so
+// the response is never materialized.
+func (env *Environment) writeUnconfirmedTxsProto(w io.Writer, page, perPage int) error {
+	var (
+		totalCount = env.Mempool.Size()
+		skipCount  = validateSkipCount(page, perPage)
+		count      = tmmath.MaxInt(0, tmmath.MinInt(perPage, totalCount-skipCount))
+		tw         = newProtoTxsWriter(w)
+	)
+	if err := tw.writeMsg(&protomempool.UnconfirmedTxsHeader{
+		Count:      int64(count),
+		Total:      int64(totalCount),
+		TotalBytes: env.Mempool.SizeBytes(),
+	}); err != nil {
+		return err
+	}
+
+	if count > 0 {
+		skipped := 0
+		env.Mempool.ForEachTx(skipCount+count, func(tx types.Tx) bool {
+			if skipped < skipCount {
+				skipped++
+				return true
+			}
+			return tw.write(tx)
+		})
+	}
+	return tw.close()
+}
+
+// writeDumpMempoolProto writes the transactions returned by unsafe_dump_mempool
+// to w as a Txs stream (see protoTxsWriter), encoded one chunk at a time while
+// iterating the mempool.
+func (env *Environment) writeDumpMempoolProto(w io.Writer) error {
+	tw := newProtoTxsWriter(w)
+	env.Mempool.ForEachExportedTx(tw.write)
+	return tw.close()
+}
+
+// protoTxsWriter writes transactions as a stream of varint length-prefixed Txs
+// messages of at most unconfirmedTxsChunkSize transactions each, ended by an
+// empty Txs message. The number of transactions is not known upfront, as the
+// mempool changes while it is iterated, so the empty message tells the reader
+// the stream is complete.
+type protoTxsWriter struct {
+	bw    *bufio.Writer
+	dw    protoio.Writer
+	chunk [][]byte
+	err   error
+}
+
+func newProtoTxsWriter(w io.Writer) *protoTxsWriter {
+	bw := bufio.NewWriter(w)
+	return &protoTxsWriter{
+		bw:    bw,
+		dw:    protoio.NewDelimitedWriter(bw),
+		chunk: make([][]byte, 0, unconfirmedTxsChunkSize),
+	}
+}
+
+func (tw *protoTxsWriter) writeMsg(msg proto.Message) error {
+	if tw.err == nil {
+		_, tw.err = tw.dw.WriteMsg(msg)
+	}
+	return tw.err
+}
+
+// write buffers tx and writes the current chunk once it is full. It reports
+// whether writing can go on, so it can be used as a mempool iterator.
+func (tw *protoTxsWriter) write(tx types.Tx) bool {
+	tw.chunk = append(tw.chunk, tx)
+	if len(tw.chunk) == cap(tw.chunk) {
+		_ = tw.writeMsg(&protomempool.Txs{Txs: tw.chunk})
+		tw.chunk = tw.chunk[:0]
+	}
+	return tw.err == nil
+}
+
+// close writes the last chunk and the empty Txs message ending the stream,
+// then flushes it.
+func (tw *protoTxsWriter) close() error {
+	if len(tw.chunk) > 0 {
+		_ = tw.writeMsg(&protomempool.Txs{Txs: tw.chunk})
+	}
+	if err := tw.writeMsg(&protomempool.Txs{}); err != nil {
+		return err
+	}
+	return tw.bw.Flush()
+}
+
+// mempoolInfo returns the state of the mempool reported by status and health.
+func (env *Environment) mempoolInfo() coretypes.MempoolInfo {
+	return coretypes.MempoolInfo{
+		Degraded:       env.Mempool.IsDegraded(),
+		CheckTxBreaker: env.Mempool.CheckTxBreakerState(),
+	}
+}
+
 // NumUnconfirmedTxs gets number of unconfirmed transactions.
 // More: https://docs.tendermint.com/master/rpc/#/Info/num_unconfirmed_txs
 func (env *Environment) NumUnconfirmedTxs(ctx context.Context) (*coretypes.ResultUnconfirmedTxs, error) {
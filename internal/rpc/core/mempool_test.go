@@ -0,0 +1,324 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	abciclient "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/mempool"
+	"github.com/tendermint/tendermint/internal/mempool/mocks"
+	"github.com/tendermint/tendermint/libs/log"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	"github.com/tendermint/tendermint/rpc/coretypes"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+func makeUnconfirmedTxs(n int) types.Txs {
+	txs := make(types.Txs, n)
+	for i := range txs {
+		txs[i] = types.Tx(fmt.Sprintf("tx-%08d-%s", i, bytes.Repeat([]byte{'x'}, 200)))
+	}
+	return txs
+}
+
+func newMempoolEnv(txs types.Txs) *Environment {
+	mp := &mocks.Mempool{}
+	mp.On("Size").Return(len(txs))
+	mp.On("SizeBytes").Return(int64(len(txs) * len(txs[0])))
+	mp.On("ReapMaxTxs", mock.AnythingOfType("int")).Return(func(max int) types.Txs {
+		if max > len(txs) {
+			max = len(txs)
+		}
+		return txs[:max]
+	})
+	mp.On("ForEachTx", mock.AnythingOfType("int"), mock.Anything).Run(func(args mock.Arguments) {
+		max, fn := args.Int(0), args.Get(1).(func(types.Tx) bool)
+		for i := 0; i < max && i < len(txs) && fn(txs[i]); i++ {
+		}
+	})
+	mp.On("ForEachExportedTx", mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(0).(func(types.Tx) bool)
+		for i := 0; i < len(txs) && fn(txs[i]); i++ {
+		}
+	})
+
+	cfg := config.TestRPCConfig()
+	cfg.Unsafe = true
+	return &Environment{
+		Mempool: mp,
+		Config:  *cfg,
+		Logger:  log.NewNopLogger(),
+	}
+}
+
+func TestUnconfirmedTxsProtoEncoding(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	txs := makeUnconfirmedTxs(2500)
+	env := newMempoolEnv(txs)
+
+	fallback := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	srv := httptest.NewServer(env.withProtoEncoding(fallback))
+	defer srv.Close()
+
+	t.Run("JSONIsDefault", func(t *testing.T) {
+		rsp, err := http.Get(srv.URL + "/unconfirmed_txs?per_page=10")
+		require.NoError(t, err)
+		rsp.Body.Close()
+		require.Equal(t, http.StatusTeapot, rsp.StatusCode)
+	})
+
+	t.Run("QueryParameter", func(t *testing.T) {
+		rsp, err := http.Get(srv.URL + "/unconfirmed_txs?encoding=proto&per_page=10")
+		require.NoError(t, err)
+		rsp.Body.Close()
+		require.Equal(t, http.StatusOK, rsp.StatusCode)
+		require.Equal(t, coretypes.ProtoContentType, rsp.Header.Get("Content-Type"))
+	})
+
+	t.Run("InvalidParameter", func(t *testing.T) {
+		rsp, err := http.Get(srv.URL + "/unconfirmed_txs?encoding=proto&page=one")
+		require.NoError(t, err)
+		rsp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, rsp.StatusCode)
+	})
+
+	t.Run("Client", func(t *testing.T) {
+		c, err := rpchttp.New(srv.URL)
+		require.NoError(t, err)
+
+		page, perPage := 1, len(txs)
+		var got types.Txs
+		res, err := c.UnconfirmedTxsStream(ctx, &page, &perPage, func(tx types.Tx) error {
+			got = append(got, tx)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, len(txs), res.Count)
+		require.Equal(t, len(txs), res.Total)
+		require.Equal(t, txs, got)
+
+		page, perPage = 3, 1000
+		got = nil
+		res, err = c.UnconfirmedTxsStream(ctx, &page, &perPage, func(tx types.Tx) error {
+			got = append(got, tx)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 500, res.Count)
+		require.Equal(t, txs[2000:], got)
+
+		// the stream may end before the header's count, if txs left the mempool
+		page, perPage = 1, 10
+		got = nil
+		res, err = c.UnconfirmedTxsStream(ctx, &page, &perPage, func(tx types.Tx) error {
+			got = append(got, tx)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 10, res.Count)
+		require.Equal(t, txs[:10], got)
+	})
+
+	t.Run("DumpMempool", func(t *testing.T) {
+		c, err := rpchttp.New(srv.URL)
+		require.NoError(t, err)
+
+		var got types.Txs
+		n, err := c.UnsafeDumpMempoolStream(ctx, func(tx types.Tx) error {
+			got = append(got, tx)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, len(txs), n)
+		require.Equal(t, txs, got)
+
+		// only with unsafe routes enabled
+		env.Config.Unsafe = false
+		defer func() { env.Config.Unsafe = true }()
+		rsp, err := http.Get(srv.URL + "/unsafe_dump_mempool?encoding=proto")
+		require.NoError(t, err)
+		rsp.Body.Close()
+		require.Equal(t, http.StatusTeapot, rsp.StatusCode)
+	})
+}
+
+func TestUnconfirmedTxsProtoEncodingShrinkingMempool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the mempool holds fewer txs by the time it is iterated
+	txs := makeUnconfirmedTxs(100)
+	env := newMempoolEnv(txs[:40])
+	mp := env.Mempool.(*mocks.Mempool)
+	mp.ExpectedCalls = nil
+	mp.On("Size").Return(len(txs))
+	mp.On("SizeBytes").Return(int64(len(txs) * len(txs[0])))
+	mp.On("ForEachTx", 50, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(types.Tx) bool)
+		for i := 0; i < 40 && fn(txs[i]); i++ {
+		}
+	})
+
+	srv := httptest.NewServer(env.withProtoEncoding(http.NotFoundHandler()))
+	defer srv.Close()
+	c, err := rpchttp.New(srv.URL)
+	require.NoError(t, err)
+
+	page, perPage := 2, 25
+	var got types.Txs
+	res, err := c.UnconfirmedTxsStream(ctx, &page, &perPage, func(tx types.Tx) error {
+		got = append(got, tx)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 15, res.Count)
+	require.Equal(t, 100, res.Total)
+	require.Equal(t, txs[25:40], got)
+}
+
+func TestBroadcastTxPrivate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx := types.Tx("private tx")
+	mp := &mocks.Mempool{}
+	mp.On("CheckTx", mock.Anything, tx, mock.Anything, mempool.TxInfo{Private: true, RPC: true}).
+		Run(func(args mock.Arguments) {
+			args.Get(2).(func(*abci.ResponseCheckTx))(&abci.ResponseCheckTx{Log: "ok"})
+		}).
+		Return(nil)
+	env := &Environment{Mempool: mp, Logger: log.NewNopLogger()}
+
+	res, err := env.BroadcastTxPrivate(ctx, &coretypes.RequestBroadcastTx{Tx: tx})
+	require.NoError(t, err)
+	require.Equal(t, "ok", res.Log)
+	require.EqualValues(t, tx.Hash(), res.Hash)
+	mp.AssertExpectations(t)
+}
+
+func TestUnconfirmedTxsHidesPrivateTxs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), kvstore.NewApplication())
+	require.NoError(t, client.Start(ctx))
+	t.Cleanup(client.Wait)
+
+	mp := mempool.NewTxMempool(log.NewNopLogger(), config.TestMempoolConfig(), client, nil)
+	env := &Environment{Mempool: mp, Logger: log.NewNopLogger()}
+
+	public, private := types.Tx("public tx"), types.Tx("private tx")
+	require.NoError(t, mp.CheckTx(ctx, public, nil, mempool.TxInfo{}))
+	_, err := env.BroadcastTxPrivate(ctx, &coretypes.RequestBroadcastTx{Tx: private})
+	require.NoError(t, err)
+
+	res, err := env.UnconfirmedTxs(ctx, &coretypes.RequestUnconfirmedTxs{})
+	require.NoError(t, err)
+	require.Equal(t, []types.Tx{public}, res.Txs)
+
+	// private txs are still proposed
+	require.ElementsMatch(t, types.Txs{public, private}, mp.ReapMaxBytesMaxGas(-1, -1))
+}
+
+func TestUnsafeLoadMempool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	txs := types.Txs{types.Tx("tx1"), types.Tx("tx2")}
+	mp := &mocks.Mempool{}
+	mp.On("ExportTxs").Return(txs)
+	mp.On("ImportTxs", mock.Anything, txs).Return(1, nil)
+	env := &Environment{Mempool: mp, Logger: log.NewNopLogger()}
+
+	dump, err := env.UnsafeDumpMempool(ctx)
+	require.NoError(t, err)
+
+	res, err := env.UnsafeLoadMempool(ctx, &coretypes.RequestUnsafeLoadMempool{Txs: dump.Txs})
+	require.NoError(t, err)
+	require.Equal(t, 2, res.Total)
+	require.Equal(t, 1, res.Accepted)
+	mp.AssertExpectations(t)
+}
+
+func TestUnsafeUpdateMempoolConfig(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := config.DefaultMempoolConfig()
+	mp := &mocks.Mempool{}
+	mp.On("UpdateConfig", mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(0).(func(*config.MempoolConfig))(cfg)
+		}).
+		Return(nil)
+	env := &Environment{Mempool: mp, Logger: log.NewNopLogger()}
+
+	size := coretypes.Int64(10)
+	rate := 2.5
+	_, err := env.UnsafeUpdateMempoolConfig(ctx, &coretypes.RequestUnsafeUpdateMempoolConfig{
+		Size:            &size,
+		PeerTxRateLimit: &rate,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 10, cfg.Size)
+	require.Equal(t, 2.5, cfg.PeerTxRateLimit)
+
+	// parameters that are not set keep their value
+	require.Equal(t, config.DefaultMempoolConfig().MaxTxsBytes, cfg.MaxTxsBytes)
+	require.Equal(t, config.DefaultMempoolConfig().EvictionPolicy, cfg.EvictionPolicy)
+	mp.AssertExpectations(t)
+}
+
+// BenchmarkUnconfirmedTxsEncoding compares the JSON and binary (proto)
+// encodings of an unconfirmed_txs response holding 100k transactions. The
+// resp-bytes metric reports the size of the encoded response.
+func BenchmarkUnconfirmedTxsEncoding(b *testing.B) {
+	txs := makeUnconfirmedTxs(100000)
+	env := newMempoolEnv(txs)
+	res := &coretypes.ResultUnconfirmedTxs{
+		Count:      len(txs),
+		Total:      len(txs),
+		TotalBytes: int64(len(txs) * len(txs[0])),
+		Txs:        txs,
+	}
+
+	b.Run("JSON", func(b *testing.B) {
+		b.ReportAllocs()
+		var size int
+		for i := 0; i < b.N; i++ {
+			rsp := rpctypes.NewRequest(-1).MakeResponse(res)
+			if rsp.Error != nil {
+				b.Fatal(rsp.Error)
+			}
+			size = len(rsp.Result)
+		}
+		b.ReportMetric(float64(size), "resp-bytes")
+	})
+
+	b.Run("Proto", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf bytes.Buffer
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			if err := env.writeUnconfirmedTxsProto(&buf, 1, len(txs)); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(buf.Len()), "resp-bytes")
+	})
+}
@@ -53,6 +53,7 @@ func NewRoutesMap(svc RPCService, opts *RouteOptions) RoutesMap {
 		"tx_search":            rpc.NewRPCFunc(svc.TxSearch),
 		"block_search":         rpc.NewRPCFunc(svc.BlockSearch),
 		"validators":           rpc.NewRPCFunc(svc.Validators),
+		"next_proposers":       rpc.NewRPCFunc(svc.NextProposers),
 		"dump_consensus_state": rpc.NewRPCFunc(svc.DumpConsensusState),
 		"consensus_state":      rpc.NewRPCFunc(svc.GetConsensusState),
 		"consensus_params":     rpc.NewRPCFunc(svc.ConsensusParams),
@@ -60,7 +61,8 @@ func NewRoutesMap(svc RPCService, opts *RouteOptions) RoutesMap {
 		"num_unconfirmed_txs":  rpc.NewRPCFunc(svc.NumUnconfirmedTxs),
 
 		// tx broadcast API
-		"broadcast_tx": rpc.NewRPCFunc(svc.BroadcastTx),
+		"broadcast_tx":         rpc.NewRPCFunc(svc.BroadcastTx),
+		"broadcast_tx_private": rpc.NewRPCFunc(svc.BroadcastTxPrivate),
 		// TODO remove after 0.36
 		// deprecated broadcast tx methods:
 		"broadcast_tx_commit": rpc.NewRPCFunc(svc.BroadcastTxCommit),
@@ -76,6 +78,9 @@ func NewRoutesMap(svc RPCService, opts *RouteOptions) RoutesMap {
 	}
 	if u, ok := svc.(RPCUnsafe); ok && opts.Unsafe {
 		out["unsafe_flush_mempool"] = rpc.NewRPCFunc(u.UnsafeFlushMempool)
+		out["unsafe_update_mempool_config"] = rpc.NewRPCFunc(u.UnsafeUpdateMempoolConfig)
+		out["unsafe_dump_mempool"] = rpc.NewRPCFunc(u.UnsafeDumpMempool)
+		out["unsafe_load_mempool"] = rpc.NewRPCFunc(u.UnsafeLoadMempool)
 	}
 	return out
 }
@@ -94,6 +99,7 @@ type RPCService interface {
 	BroadcastTx(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error)
 	BroadcastTxAsync(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error)
 	BroadcastTxCommit(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTxCommit, error)
+	BroadcastTxPrivate(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error)
 	BroadcastTxSync(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error)
 	CheckTx(ctx context.Context, req *coretypes.RequestCheckTx) (*coretypes.ResultCheckTx, error)
 	Commit(ctx context.Context, req *coretypes.RequestBlockInfo) (*coretypes.ResultCommit, error)
@@ -107,6 +113,7 @@ type RPCService interface {
 	HeaderByHash(ctx context.Context, req *coretypes.RequestBlockByHash) (*coretypes.ResultHeader, error)
 	Health(ctx context.Context) (*coretypes.ResultHealth, error)
 	NetInfo(ctx context.Context) (*coretypes.ResultNetInfo, error)
+	NextProposers(ctx context.Context, req *coretypes.RequestNextProposers) (*coretypes.ResultNextProposers, error)
 	NumUnconfirmedTxs(ctx context.Context) (*coretypes.ResultUnconfirmedTxs, error)
 	RemoveTx(ctx context.Context, req *coretypes.RequestRemoveTx) error
 	Status(ctx context.Context) (*coretypes.ResultStatus, error)
@@ -124,4 +131,7 @@ type RPCService interface {
 // exported by the RPC service.
 type RPCUnsafe interface {
 	UnsafeFlushMempool(ctx context.Context) (*coretypes.ResultUnsafeFlushMempool, error)
+	UnsafeUpdateMempoolConfig(ctx context.Context, req *coretypes.RequestUnsafeUpdateMempoolConfig) (*coretypes.ResultUnsafeUpdateMempoolConfig, error)
+	UnsafeDumpMempool(ctx context.Context) (*coretypes.ResultUnsafeDumpMempool, error)
+	UnsafeLoadMempool(ctx context.Context, req *coretypes.RequestUnsafeLoadMempool) (*coretypes.ResultUnsafeLoadMempool, error)
 }
@@ -96,6 +96,10 @@ func (env *Environment) Status(ctx context.Context) (*coretypes.ResultStatus, er
 		result.SyncInfo.RemainingTime = env.BlockSyncReactor.GetRemainingSyncTime()
 	}
 
+	if env.Mempool != nil {
+		result.MempoolInfo = env.mempoolInfo()
+	}
+
 	if env.StateSyncMetricer != nil {
 		result.SyncInfo.TotalSnapshots = env.StateSyncMetricer.TotalSnapshots()
 		result.SyncInfo.ChunkProcessAvgTime = env.StateSyncMetricer.ChunkProcessAvgTime()
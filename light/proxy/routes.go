@@ -60,6 +60,10 @@ func (p proxyService) BroadcastTxCommit(ctx context.Context, req *coretypes.Requ
 	return p.Client.BroadcastTxCommit(ctx, req.Tx)
 }
 
+func (p proxyService) BroadcastTxPrivate(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error) {
+	return p.Client.BroadcastTxPrivate(ctx, req.Tx)
+}
+
 func (p proxyService) BroadcastTxSync(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error) {
 	return p.Client.BroadcastTxSync(ctx, req.Tx)
 }
@@ -112,6 +116,10 @@ func (p proxyService) NetInfo(ctx context.Context) (*coretypes.ResultNetInfo, er
 	return p.Client.NetInfo(ctx)
 }
 
+func (p proxyService) NextProposers(ctx context.Context, req *coretypes.RequestNextProposers) (*coretypes.ResultNextProposers, error) {
+	return p.Client.NextProposers(ctx, req.Count.IntPtr())
+}
+
 func (p proxyService) NumUnconfirmedTxs(ctx context.Context) (*coretypes.ResultUnconfirmedTxs, error) {
 	return p.Client.NumUnconfirmedTxs(ctx)
 }
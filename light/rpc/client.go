@@ -239,6 +239,10 @@ func (c *Client) BroadcastTx(ctx context.Context, tx types.Tx) (*coretypes.Resul
 	return c.next.BroadcastTx(ctx, tx)
 }
 
+func (c *Client) BroadcastTxPrivate(ctx context.Context, tx types.Tx) (*coretypes.ResultBroadcastTx, error) {
+	return c.next.BroadcastTxPrivate(ctx, tx)
+}
+
 func (c *Client) UnconfirmedTxs(ctx context.Context, page, perPage *int) (*coretypes.ResultUnconfirmedTxs, error) {
 	return c.next.UnconfirmedTxs(ctx, page, perPage)
 }
@@ -600,6 +604,12 @@ func (c *Client) Validators(
 	}, nil
 }
 
+// NextProposers does not verify the result: it is a prediction that cannot be
+// checked against a light block.
+func (c *Client) NextProposers(ctx context.Context, count *int) (*coretypes.ResultNextProposers, error) {
+	return c.next.NextProposers(ctx, count)
+}
+
 func (c *Client) BroadcastEvidence(ctx context.Context, ev types.Evidence) (*coretypes.ResultBroadcastEvidence, error) {
 	return c.next.BroadcastEvidence(ctx, ev)
 }
@@ -289,7 +289,7 @@ func makeNode(
 	shoulddbsync := cfg.DBSync.Enable && info.LastBlockHeight == 0
 
 	mpReactor, mp := createMempoolReactor(logger, cfg, proxyApp, stateStore, nodeMetrics.mempool,
-		peerManager.Subscribe, peerManager)
+		peerManager.Subscribe, peerManager, tracerProviderOptions, eventBus)
 	node.router.AddChDescToBeAdded(mempool.GetChannelDescriptor(cfg.Mempool), mpReactor.SetChannel)
 	if !shoulddbsync {
 		mpReactor.MarkReadyToStart()
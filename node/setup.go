@@ -9,6 +9,7 @@ import (
 	"time"
 
 	dbm "github.com/tendermint/tm-db"
+	"go.opentelemetry.io/otel/sdk/trace"
 
 	abciclient "github.com/tendermint/tendermint/abci/client"
 	"github.com/tendermint/tendermint/config"
@@ -146,6 +147,8 @@ func createMempoolReactor(
 	memplMetrics *mempool.Metrics,
 	peerEvents p2p.PeerEventSubscriber,
 	peerManager *p2p.PeerManager,
+	tracerProviderOptions []trace.TracerProviderOption,
+	eventBus *eventbus.EventBus,
 ) (*mempool.Reactor, mempool.Mempool) {
 	logger = logger.With("module", "mempool")
 
@@ -157,6 +160,8 @@ func createMempoolReactor(
 		mempool.WithMetrics(memplMetrics),
 		mempool.WithPreCheck(sm.TxPreCheckFromStore(store)),
 		mempool.WithPostCheck(sm.TxPostCheckFromStore(store)),
+		mempool.WithTracerProviderOptions(tracerProviderOptions),
+		mempool.WithEventPublisher(eventBus),
 	)
 
 	reactor := mempool.NewReactor(
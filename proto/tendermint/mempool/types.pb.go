@@ -68,7 +68,6 @@ func (m *Txs) GetTxs() [][]byte {
 
 type Message struct {
 	// Types that are valid to be assigned to Sum:
-	//
 	//	*Message_Txs
 	Sum isMessage_Sum `protobuf_oneof:"sum"`
 }
@@ -139,27 +138,187 @@ func (*Message) XXX_OneofWrappers() []interface{} {
 	}
 }
 
+// WALRecord is a record of the mempool's write-ahead log. It records either a
+// transaction admitted to the mempool or, by its key, the removal of one. A
+// tx record is encoded like a Txs message holding a single transaction.
+type WALRecord struct {
+	// Types that are valid to be assigned to Sum:
+	//	*WALRecord_Tx
+	//	*WALRecord_Removed
+	Sum isWALRecord_Sum `protobuf_oneof:"sum"`
+}
+
+func (m *WALRecord) Reset()         { *m = WALRecord{} }
+func (m *WALRecord) String() string { return proto.CompactTextString(m) }
+func (*WALRecord) ProtoMessage()    {}
+func (*WALRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{2}
+}
+func (m *WALRecord) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WALRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WALRecord.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *WALRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WALRecord.Merge(m, src)
+}
+func (m *WALRecord) XXX_Size() int {
+	return m.Size()
+}
+func (m *WALRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_WALRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WALRecord proto.InternalMessageInfo
+
+type isWALRecord_Sum interface {
+	isWALRecord_Sum()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type WALRecord_Tx struct {
+	Tx []byte `protobuf:"bytes,1,opt,name=tx,proto3,oneof" json:"tx,omitempty"`
+}
+type WALRecord_Removed struct {
+	Removed []byte `protobuf:"bytes,2,opt,name=removed,proto3,oneof" json:"removed,omitempty"`
+}
+
+func (*WALRecord_Tx) isWALRecord_Sum()      {}
+func (*WALRecord_Removed) isWALRecord_Sum() {}
+
+func (m *WALRecord) GetSum() isWALRecord_Sum {
+	if m != nil {
+		return m.Sum
+	}
+	return nil
+}
+
+func (m *WALRecord) GetTx() []byte {
+	if x, ok := m.GetSum().(*WALRecord_Tx); ok {
+		return x.Tx
+	}
+	return nil
+}
+
+func (m *WALRecord) GetRemoved() []byte {
+	if x, ok := m.GetSum().(*WALRecord_Removed); ok {
+		return x.Removed
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*WALRecord) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*WALRecord_Tx)(nil),
+		(*WALRecord_Removed)(nil),
+	}
+}
+
+// UnconfirmedTxsHeader is the first message of the binary (proto) encoding
+// of the unconfirmed_txs RPC response. It is followed by Txs messages, each
+// varint length-prefixed, ended by an empty one. They carry at most count
+// transactions, fewer if some left the mempool while it was streamed.
+type UnconfirmedTxsHeader struct {
+	Count      int64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Total      int64 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	TotalBytes int64 `protobuf:"varint,3,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+}
+
+func (m *UnconfirmedTxsHeader) Reset()         { *m = UnconfirmedTxsHeader{} }
+func (m *UnconfirmedTxsHeader) String() string { return proto.CompactTextString(m) }
+func (*UnconfirmedTxsHeader) ProtoMessage()    {}
+func (*UnconfirmedTxsHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{3}
+}
+func (m *UnconfirmedTxsHeader) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UnconfirmedTxsHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UnconfirmedTxsHeader.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UnconfirmedTxsHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnconfirmedTxsHeader.Merge(m, src)
+}
+func (m *UnconfirmedTxsHeader) XXX_Size() int {
+	return m.Size()
+}
+func (m *UnconfirmedTxsHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnconfirmedTxsHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnconfirmedTxsHeader proto.InternalMessageInfo
+
+func (m *UnconfirmedTxsHeader) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *UnconfirmedTxsHeader) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *UnconfirmedTxsHeader) GetTotalBytes() int64 {
+	if m != nil {
+		return m.TotalBytes
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Txs)(nil), "tendermint.mempool.Txs")
 	proto.RegisterType((*Message)(nil), "tendermint.mempool.Message")
+	proto.RegisterType((*WALRecord)(nil), "tendermint.mempool.WALRecord")
+	proto.RegisterType((*UnconfirmedTxsHeader)(nil), "tendermint.mempool.UnconfirmedTxsHeader")
 }
 
 func init() { proto.RegisterFile("tendermint/mempool/types.proto", fileDescriptor_2af51926fdbcbc05) }
 
 var fileDescriptor_2af51926fdbcbc05 = []byte{
-	// 179 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x2b, 0x49, 0xcd, 0x4b,
-	0x49, 0x2d, 0xca, 0xcd, 0xcc, 0x2b, 0xd1, 0xcf, 0x4d, 0xcd, 0x2d, 0xc8, 0xcf, 0xcf, 0xd1, 0x2f,
-	0xa9, 0x2c, 0x48, 0x2d, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x12, 0x42, 0xc8, 0xeb, 0x41,
-	0xe5, 0x95, 0xc4, 0xb9, 0x98, 0x43, 0x2a, 0x8a, 0x85, 0x04, 0xb8, 0x98, 0x4b, 0x2a, 0x8a, 0x25,
-	0x18, 0x15, 0x98, 0x35, 0x78, 0x82, 0x40, 0x4c, 0x25, 0x5b, 0x2e, 0x76, 0xdf, 0xd4, 0xe2, 0xe2,
-	0xc4, 0xf4, 0x54, 0x21, 0x6d, 0x98, 0x24, 0xa3, 0x06, 0xb7, 0x91, 0xb8, 0x1e, 0xa6, 0x29, 0x7a,
-	0x21, 0x15, 0xc5, 0x1e, 0x0c, 0x60, 0x7d, 0x4e, 0xac, 0x5c, 0xcc, 0xc5, 0xa5, 0xb9, 0x4e, 0xc1,
-	0x27, 0x1e, 0xc9, 0x31, 0x5e, 0x78, 0x24, 0xc7, 0xf8, 0xe0, 0x91, 0x1c, 0xe3, 0x84, 0xc7, 0x72,
-	0x0c, 0x17, 0x1e, 0xcb, 0x31, 0xdc, 0x78, 0x2c, 0xc7, 0x10, 0x65, 0x99, 0x9e, 0x59, 0x92, 0x51,
-	0x9a, 0xa4, 0x97, 0x9c, 0x9f, 0xab, 0x8f, 0xe4, 0x60, 0x24, 0x26, 0xd8, 0xb5, 0xfa, 0x98, 0x9e,
-	0x49, 0x62, 0x03, 0xcb, 0x18, 0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0xca, 0xc3, 0xa0, 0xfc, 0xe9,
-	0x00, 0x00, 0x00,
+	// 287 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0x3f, 0x4b, 0xc3, 0x40,
+	0x18, 0xc6, 0x93, 0x1e, 0xb5, 0xf8, 0xb6, 0x43, 0x39, 0x0a, 0x2d, 0x0e, 0xa7, 0x74, 0x2a, 0x08,
+	0x09, 0xe8, 0xe4, 0x20, 0x68, 0xa7, 0x0e, 0xba, 0xc4, 0x8a, 0xe0, 0x22, 0xf9, 0xf3, 0x5a, 0x03,
+	0xbd, 0x5c, 0xb8, 0x7b, 0x23, 0xe9, 0xb7, 0xf0, 0x63, 0x39, 0x76, 0x74, 0x94, 0xe4, 0x8b, 0x48,
+	0xae, 0x0d, 0x16, 0xba, 0x3d, 0xf7, 0xfb, 0xf1, 0x3e, 0x07, 0x0f, 0x08, 0xc2, 0x2c, 0x41, 0x2d,
+	0xd3, 0x8c, 0x7c, 0x89, 0x32, 0x57, 0x6a, 0xed, 0xd3, 0x26, 0x47, 0xe3, 0xe5, 0x5a, 0x91, 0xe2,
+	0xfc, 0xdf, 0x7b, 0x7b, 0x3f, 0x1d, 0x03, 0x5b, 0x96, 0x86, 0x0f, 0x81, 0x51, 0x69, 0x26, 0xee,
+	0x05, 0x9b, 0x0d, 0x82, 0x26, 0x4e, 0x6f, 0xa1, 0xf7, 0x88, 0xc6, 0x84, 0x2b, 0xe4, 0x97, 0xad,
+	0x74, 0x67, 0xfd, 0xab, 0xb1, 0x77, 0xdc, 0xe2, 0x2d, 0x4b, 0xb3, 0x70, 0xec, 0xdd, 0xbc, 0x0b,
+	0xcc, 0x14, 0x72, 0x7a, 0x07, 0xa7, 0x2f, 0xf7, 0x0f, 0x01, 0xc6, 0x4a, 0x27, 0x7c, 0x08, 0x1d,
+	0x2a, 0xed, 0xfd, 0x60, 0xe1, 0x04, 0x1d, 0x2a, 0xf9, 0x19, 0xf4, 0x34, 0x4a, 0xf5, 0x89, 0xc9,
+	0xa4, 0xb3, 0xc7, 0x2d, 0x68, 0x1b, 0x62, 0x18, 0x3d, 0x67, 0xb1, 0xca, 0xde, 0x53, 0x2d, 0x31,
+	0x69, 0x7e, 0xc0, 0x30, 0x41, 0xcd, 0x47, 0xd0, 0x8d, 0x55, 0x91, 0x91, 0xed, 0x63, 0xc1, 0xee,
+	0xd1, 0x50, 0x52, 0x14, 0xae, 0x6d, 0x1d, 0x0b, 0x76, 0x0f, 0x7e, 0x0e, 0x7d, 0x1b, 0xde, 0xa2,
+	0x0d, 0xa1, 0x99, 0x30, 0xeb, 0xc0, 0xa2, 0x79, 0x43, 0xe6, 0x4f, 0xdf, 0x95, 0x70, 0xb7, 0x95,
+	0x70, 0x7f, 0x2b, 0xe1, 0x7e, 0xd5, 0xc2, 0xd9, 0xd6, 0xc2, 0xf9, 0xa9, 0x85, 0xf3, 0x7a, 0xb3,
+	0x4a, 0xe9, 0xa3, 0x88, 0xbc, 0x58, 0x49, 0xff, 0x60, 0xd7, 0x83, 0x68, 0x47, 0xf5, 0x8f, 0x37,
+	0x8f, 0x4e, 0xac, 0xb9, 0xfe, 0x1b, 0x00, 0x73, 0xe1, 0xa3, 0x30, 0x90, 0x01, 0x00, 0x00,
 }
 
 func (m *Txs) Marshal() (dAtA []byte, err error) {
@@ -247,6 +406,108 @@ func (m *Message_Txs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	}
 	return len(dAtA) - i, nil
 }
+func (m *WALRecord) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WALRecord) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WALRecord) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Sum != nil {
+		{
+			size := m.Sum.Size()
+			i -= size
+			if _, err := m.Sum.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *WALRecord_Tx) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WALRecord_Tx) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Tx != nil {
+		i -= len(m.Tx)
+		copy(dAtA[i:], m.Tx)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Tx)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+func (m *WALRecord_Removed) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WALRecord_Removed) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Removed != nil {
+		i -= len(m.Removed)
+		copy(dAtA[i:], m.Removed)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Removed)))
+		i--
+		dAtA[i] = 0x12
+	}
+	return len(dAtA) - i, nil
+}
+func (m *UnconfirmedTxsHeader) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UnconfirmedTxsHeader) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UnconfirmedTxsHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.TotalBytes != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.TotalBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Total != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Total))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Count != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTypes(v)
 	base := offset
@@ -297,6 +558,59 @@ func (m *Message_Txs) Size() (n int) {
 	}
 	return n
 }
+func (m *WALRecord) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Sum != nil {
+		n += m.Sum.Size()
+	}
+	return n
+}
+
+func (m *WALRecord_Tx) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Tx != nil {
+		l = len(m.Tx)
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *WALRecord_Removed) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Removed != nil {
+		l = len(m.Removed)
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *UnconfirmedTxsHeader) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Count != 0 {
+		n += 1 + sovTypes(uint64(m.Count))
+	}
+	if m.Total != 0 {
+		n += 1 + sovTypes(uint64(m.Total))
+	}
+	if m.TotalBytes != 0 {
+		n += 1 + sovTypes(uint64(m.TotalBytes))
+	}
+	return n
+}
 
 func sovTypes(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
@@ -471,6 +785,229 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *WALRecord) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WALRecord: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WALRecord: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tx", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := make([]byte, postIndex-iNdEx)
+			copy(v, dAtA[iNdEx:postIndex])
+			m.Sum = &WALRecord_Tx{v}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Removed", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := make([]byte, postIndex-iNdEx)
+			copy(v, dAtA[iNdEx:postIndex])
+			m.Sum = &WALRecord_Removed{v}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UnconfirmedTxsHeader) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UnconfirmedTxsHeader: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UnconfirmedTxsHeader: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
+			}
+			m.Total = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Total |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalBytes", wireType)
+			}
+			m.TotalBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipTypes(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1,12 +1,18 @@
 package http
 
 import (
+	"bufio"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
+	"github.com/tendermint/tendermint/internal/libs/protoio"
 	"github.com/tendermint/tendermint/libs/bytes"
+	protomempool "github.com/tendermint/tendermint/proto/tendermint/mempool"
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	"github.com/tendermint/tendermint/rpc/coretypes"
 	jsonrpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
@@ -98,6 +104,10 @@ var (
 	_ rpcClient = (*baseRPCClient)(nil)
 )
 
+// maxUnconfirmedTxsMsgSize bounds the size of a single message read from a
+// binary unconfirmed_txs response.
+const maxUnconfirmedTxsMsgSize = 1 << 30
+
 //-----------------------------------------------------------------------------
 // HTTP
 
@@ -254,6 +264,10 @@ func (c *baseRPCClient) BroadcastTx(ctx context.Context, tx types.Tx) (*coretype
 	return c.broadcastTX(ctx, "broadcast_tx_sync", tx)
 }
 
+func (c *baseRPCClient) BroadcastTxPrivate(ctx context.Context, tx types.Tx) (*coretypes.ResultBroadcastTx, error) {
+	return c.broadcastTX(ctx, "broadcast_tx_private", tx)
+}
+
 func (c *baseRPCClient) broadcastTX(ctx context.Context, route string, tx types.Tx) (*coretypes.ResultBroadcastTx, error) {
 	result := new(coretypes.ResultBroadcastTx)
 	if err := c.caller.Call(ctx, route, &coretypes.RequestBroadcastTx{Tx: tx}, result); err != nil {
@@ -274,6 +288,89 @@ func (c *baseRPCClient) UnconfirmedTxs(ctx context.Context, page *int, perPage *
 	return result, nil
 }
 
+// UnconfirmedTxsStream fetches unconfirmed transactions using the binary
+// (proto) encoding of the unconfirmed_txs endpoint, which avoids the cost of
+// JSON-encoding large mempools. Transactions are passed to fn one at a time as
+// they are decoded from the response stream; if fn returns an error, reading
+// stops and that error is returned. The returned result carries the counts of
+// the response but no transactions; its Count is the number of transactions
+// passed to fn.
+func (c *HTTP) UnconfirmedTxsStream(
+	ctx context.Context,
+	page *int,
+	perPage *int,
+	fn func(types.Tx) error,
+) (*coretypes.ResultUnconfirmedTxs, error) {
+	query := url.Values{}
+	if page != nil {
+		query.Set("page", strconv.Itoa(*page))
+	}
+	if perPage != nil {
+		query.Set("per_page", strconv.Itoa(*perPage))
+	}
+
+	body, err := c.rpc.Get(ctx, "unconfirmed_txs", query, coretypes.ProtoContentType)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	r := protoio.NewDelimitedReader(bufio.NewReader(body), maxUnconfirmedTxsMsgSize)
+
+	var header protomempool.UnconfirmedTxsHeader
+	if _, err := r.ReadMsg(&header); err != nil {
+		return nil, fmt.Errorf("reading unconfirmed txs header: %w", err)
+	}
+
+	n, err := readTxsStream(r, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &coretypes.ResultUnconfirmedTxs{
+		Count:      n,
+		Total:      int(header.Total),
+		TotalBytes: header.TotalBytes,
+	}, nil
+}
+
+// UnsafeDumpMempoolStream fetches the transactions in the mempool using the
+// binary (proto) encoding of the unsafe_dump_mempool endpoint. Transactions
+// are passed to fn one at a time, like UnconfirmedTxsStream does, and their
+// number is returned.
+func (c *HTTP) UnsafeDumpMempoolStream(ctx context.Context, fn func(types.Tx) error) (int, error) {
+	body, err := c.rpc.Get(ctx, "unsafe_dump_mempool", nil, coretypes.ProtoContentType)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	r := protoio.NewDelimitedReader(bufio.NewReader(body), maxUnconfirmedTxsMsgSize)
+
+	return readTxsStream(r, fn)
+}
+
+// readTxsStream passes the transactions of the Txs messages read from r to fn
+// until the empty message ending the stream, and returns their number. A
+// stream cut before that message is an error. An error returned by fn is
+// returned as is.
+func readTxsStream(r protoio.Reader, fn func(types.Tx) error) (int, error) {
+	var n int
+	for {
+		var txs protomempool.Txs
+		if _, err := r.ReadMsg(&txs); err != nil {
+			return n, fmt.Errorf("reading txs: %w", err)
+		}
+		if len(txs.Txs) == 0 {
+			return n, nil
+		}
+		for _, tx := range txs.Txs {
+			if err := fn(tx); err != nil {
+				return n, err
+			}
+		}
+		n += len(txs.Txs)
+	}
+}
+
 func (c *baseRPCClient) NumUnconfirmedTxs(ctx context.Context) (*coretypes.ResultUnconfirmedTxs, error) {
 	result := new(coretypes.ResultUnconfirmedTxs)
 	if err := c.caller.Call(ctx, "num_unconfirmed_txs", nil, result); err != nil {
@@ -483,6 +580,16 @@ func (c *baseRPCClient) Validators(ctx context.Context, height *int64, page, per
 	return result, nil
 }
 
+func (c *baseRPCClient) NextProposers(ctx context.Context, count *int) (*coretypes.ResultNextProposers, error) {
+	result := new(coretypes.ResultNextProposers)
+	if err := c.caller.Call(ctx, "next_proposers", &coretypes.RequestNextProposers{
+		Count: coretypes.Int64Ptr(count),
+	}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) BroadcastEvidence(ctx context.Context, ev types.Evidence) (*coretypes.ResultBroadcastEvidence, error) {
 	result := new(coretypes.ResultBroadcastEvidence)
 	if err := c.caller.Call(ctx, "broadcast_evidence", &coretypes.RequestBroadcastEvidence{
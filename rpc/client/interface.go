@@ -79,6 +79,7 @@ type SignClient interface {
 	HeaderByHash(ctx context.Context, hash bytes.HexBytes) (*coretypes.ResultHeader, error)
 	Commit(ctx context.Context, height *int64) (*coretypes.ResultCommit, error)
 	Validators(ctx context.Context, height *int64, page, perPage *int) (*coretypes.ResultValidators, error)
+	NextProposers(ctx context.Context, count *int) (*coretypes.ResultNextProposers, error)
 	Tx(ctx context.Context, hash bytes.HexBytes, prove bool) (*coretypes.ResultTx, error)
 
 	// TxSearch defines a method to search for a paginated set of transactions by
@@ -168,6 +169,10 @@ type MempoolClient interface {
 	NumUnconfirmedTxs(context.Context) (*coretypes.ResultUnconfirmedTxs, error)
 	CheckTx(context.Context, types.Tx) (*coretypes.ResultCheckTx, error)
 	RemoveTx(context.Context, types.TxKey) error
+
+	// BroadcastTxPrivate submits a tx that the node keeps in its mempool
+	// without gossiping it to peers.
+	BroadcastTxPrivate(context.Context, types.Tx) (*coretypes.ResultBroadcastTx, error)
 }
 
 // EvidenceClient is used for submitting an evidence of the malicious
@@ -99,6 +99,10 @@ func (c *Local) BroadcastTxAsync(ctx context.Context, tx types.Tx) (*coretypes.R
 	return c.env.BroadcastTxAsync(ctx, &coretypes.RequestBroadcastTx{Tx: tx})
 }
 
+func (c *Local) BroadcastTxPrivate(ctx context.Context, tx types.Tx) (*coretypes.ResultBroadcastTx, error) {
+	return c.env.BroadcastTxPrivate(ctx, &coretypes.RequestBroadcastTx{Tx: tx})
+}
+
 func (c *Local) BroadcastTxSync(ctx context.Context, tx types.Tx) (*coretypes.ResultBroadcastTx, error) {
 	return c.env.BroadcastTxSync(ctx, &coretypes.RequestBroadcastTx{Tx: tx})
 }
@@ -193,6 +197,10 @@ func (c *Local) Validators(ctx context.Context, height *int64, page, perPage *in
 	})
 }
 
+func (c *Local) NextProposers(ctx context.Context, count *int) (*coretypes.ResultNextProposers, error) {
+	return c.env.NextProposers(ctx, &coretypes.RequestNextProposers{Count: coretypes.Int64Ptr(count)})
+}
+
 func (c *Local) Tx(ctx context.Context, hash bytes.HexBytes, prove bool) (*coretypes.ResultTx, error) {
 	return c.env.Tx(ctx, &coretypes.RequestTx{Hash: hash, Prove: prove})
 }
@@ -43,6 +43,22 @@ type RequestRemoveTx struct {
 	TxKey types.TxKey `json:"txkey"`
 }
 
+type RequestUnsafeLoadMempool struct {
+	Txs []types.Tx `json:"txs"`
+}
+
+type RequestUnsafeUpdateMempoolConfig struct {
+	Size               *Int64   `json:"size"`
+	MaxTxsBytes        *Int64   `json:"max_txs_bytes"`
+	PendingSize        *Int64   `json:"pending_size"`
+	MaxPendingTxsBytes *Int64   `json:"max_pending_txs_bytes"`
+	PeerTxRateLimit    *float64 `json:"peer_tx_rate_limit"`
+	PeerBytesRateLimit *Int64   `json:"peer_bytes_rate_limit"`
+	RPCTxRateLimit     *float64 `json:"rpc_tx_rate_limit"`
+	RPCBytesRateLimit  *Int64   `json:"rpc_bytes_rate_limit"`
+	EvictionPolicy     *string  `json:"eviction_policy"`
+}
+
 type RequestTx struct {
 	Hash  bytes.HexBytes `json:"hash"`
 	Prove bool           `json:"prove"`
@@ -63,6 +79,10 @@ type RequestBlockSearch struct {
 	OrderBy string `json:"order_by"`
 }
 
+type RequestNextProposers struct {
+	Count *Int64 `json:"count"`
+}
+
 type RequestValidators struct {
 	Height  *Int64 `json:"height"`
 	Page    *Int64 `json:"page"`
@@ -158,6 +158,30 @@ func (v *ValidatorInfo) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Info about the node's mempool
+type MempoolInfo struct {
+	// Degraded is true if some transactions could not be rechecked against the
+	// application after the latest block, even once retried for the mempool's
+	// recheck-retry-timeout. Those transactions are not included
+	// in proposed blocks, nor returned by unconfirmed_txs, until the app
+	// connection recovers. They are still counted by num_unconfirmed_txs and by
+	// the total of unconfirmed_txs.
+	Degraded bool `json:"degraded"`
+
+	// CheckTxBreaker is the state of the mempool's CheckTx circuit breaker:
+	// "closed", "open" while new transactions are rejected because the
+	// application is overloaded, or "half-open" while a probe transaction is
+	// checked. A disabled breaker is always closed.
+	CheckTxBreaker string `json:"check_tx_breaker"`
+}
+
+// ResultHealth reports the state of the node's subsystems. A node is healthy
+// if it answers at all; the fields tell whether it is fit for, e.g.,
+// submitting transactions.
+type ResultHealth struct {
+	Mempool MempoolInfo `json:"mempool"`
+}
+
 // Node Status
 type ResultStatus struct {
 	NodeInfo        types.NodeInfo        `json:"node_info"`
@@ -165,6 +189,7 @@ type ResultStatus struct {
 	SyncInfo        SyncInfo              `json:"sync_info"`
 	ValidatorInfo   ValidatorInfo         `json:"validator_info"`
 	LightClientInfo types.LightClientInfo `json:"light_client_info,omitempty"`
+	MempoolInfo     MempoolInfo           `json:"mempool_info"`
 }
 
 // Node lag status
@@ -223,6 +248,18 @@ type ResultValidators struct {
 	Total int `json:"total,string"` // Total number of validators
 }
 
+// Expected proposers of the next heights
+type ResultNextProposers struct {
+	Proposers []Proposer `json:"proposers"`
+}
+
+// Proposer is the validator expected to propose the block at Height, in
+// round 0
+type Proposer struct {
+	Height  int64         `json:"height,string"`
+	Address types.Address `json:"address"`
+}
+
 // ConsensusParams for given height
 type ResultConsensusParams struct {
 	BlockHeight     int64                 `json:"block_height,string"`
@@ -291,6 +328,11 @@ type ResultBlockSearch struct {
 	TotalCount int            `json:"total_count,string"`
 }
 
+// ProtoContentType is the media type of binary (proto) encoded RPC responses.
+// A client selects it on the endpoints that support it either with an Accept
+// header or with the encoding=proto query parameter.
+const ProtoContentType = "application/x-protobuf"
+
 // List of mempool txs
 type ResultUnconfirmedTxs struct {
 	Count      int        `json:"n_txs,string"`
@@ -299,6 +341,17 @@ type ResultUnconfirmedTxs struct {
 	Txs        []types.Tx `json:"txs"`
 }
 
+// List of mempool txs exported by unsafe_dump_mempool
+type ResultUnsafeDumpMempool struct {
+	Txs []types.Tx `json:"txs"`
+}
+
+// Result of importing txs with unsafe_load_mempool
+type ResultUnsafeLoadMempool struct {
+	Total    int `json:"total,string"`
+	Accepted int `json:"accepted,string"`
+}
+
 // Info abci msg
 type ResultABCIInfo struct {
 	Response abci.ResponseInfo `json:"response"`
@@ -316,11 +369,11 @@ type ResultBroadcastEvidence struct {
 
 // empty results
 type (
-	ResultUnsafeFlushMempool struct{}
-	ResultUnsafeProfile      struct{}
-	ResultSubscribe          struct{}
-	ResultUnsubscribe        struct{}
-	ResultHealth             struct{}
+	ResultUnsafeFlushMempool        struct{}
+	ResultUnsafeUpdateMempoolConfig struct{}
+	ResultUnsafeProfile             struct{}
+	ResultSubscribe                 struct{}
+	ResultUnsubscribe               struct{}
 )
 
 // Event data from a subscription
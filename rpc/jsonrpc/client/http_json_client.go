@@ -219,6 +219,43 @@ func (c *Client) Call(ctx context.Context, method string, params, result interfa
 	return unmarshalResponseBytes(responseBytes, request.ID(), result)
 }
 
+// Get issues a GET HTTP request to the URI endpoint of method with the given
+// query parameters and Accept header, and returns the response body without
+// decoding it. It is meant for endpoints that respond with a non-JSON stream.
+// The caller is responsible for closing the returned body.
+func (c *Client) Get(ctx context.Context, method string, query url.Values, accept string) (io.ReadCloser, error) {
+	address := c.address + "/" + method
+	if len(query) > 0 {
+		address += "?" + query.Encode()
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request setup failed: %w", err)
+	}
+
+	if accept != "" {
+		httpRequest.Header.Set("Accept", accept)
+	}
+
+	if c.username != "" || c.password != "" {
+		httpRequest.SetBasicAuth(c.username, c.password)
+	}
+
+	httpResponse, err := c.client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpResponse.StatusCode != http.StatusOK {
+		responseBytes, _ := io.ReadAll(httpResponse.Body)
+		httpResponse.Body.Close()
+		return nil, fmt.Errorf("%s: %s", httpResponse.Status, strings.TrimSpace(string(responseBytes)))
+	}
+
+	return httpResponse.Body, nil
+}
+
 // NewRequestBatch starts a batch of requests for this client.
 func (c *Client) NewRequestBatch() *RequestBatch {
 	return &RequestBatch{
@@ -45,6 +45,24 @@ const (
 	// Events emitted by the evidence reactor when evidence is validated
 	// and before it is committed
 	EventEvidenceValidatedValue = "EvidenceValidated"
+
+	// Events emitted by the mempool when a transaction is added or removed,
+	// if enabled in the mempool config.
+	EventMempoolTxAddedValue   = "MempoolTxAdded"
+	EventMempoolTxRemovedValue = "MempoolTxRemoved"
+)
+
+// Reasons for which a transaction is removed from the mempool, reported in
+// EventDataMempoolTx.
+const (
+	MempoolTxCommitted  = "committed"
+	MempoolTxEvicted    = "evicted"
+	MempoolTxExpired    = "expired"
+	MempoolTxFlushed    = "flushed"
+	MempoolTxInvalid    = "invalid"
+	MempoolTxReenqueued = "reenqueued"
+	MempoolTxRemoved    = "removed"
+	MempoolTxReplaced   = "replaced"
 )
 
 // Pre-populated ABCI Tendermint-reserved events
@@ -121,6 +139,7 @@ func init() {
 	jsontypes.MustRegister(EventDataValidatorSetUpdates{})
 	jsontypes.MustRegister(EventDataVote{})
 	jsontypes.MustRegister(EventDataEvidenceValidated{})
+	jsontypes.MustRegister(EventDataMempoolTx{})
 	jsontypes.MustRegister(LegacyEventDataNewBlock{})
 	jsontypes.MustRegister(LegacyEventDataTx{})
 	jsontypes.MustRegister(EventDataString(""))
@@ -448,6 +467,23 @@ func (e EventDataEvidenceValidated) ToLegacy() LegacyEventData {
 	return e
 }
 
+// EventDataMempoolTx is published when a transaction is added to or removed
+// from the mempool. Reason is only set for removed transactions. Pending
+// transactions are only reported once they are added to the mempool.
+type EventDataMempoolTx struct {
+	Tx       Tx     `json:"tx"`
+	Priority int64  `json:"priority,string"`
+	Height   int64  `json:"height,string"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// TypeTag implements the required method of jsontypes.Tagged.
+func (EventDataMempoolTx) TypeTag() string { return "tendermint/event/MempoolTx" }
+
+func (e EventDataMempoolTx) ToLegacy() LegacyEventData {
+	return e
+}
+
 // PUBSUB
 
 const (
@@ -485,6 +521,8 @@ var (
 	EventQueryBlockSyncStatus     = QueryForEvent(EventBlockSyncStatusValue)
 	EventQueryStateSyncStatus     = QueryForEvent(EventStateSyncStatusValue)
 	EventQueryEvidenceValidated   = QueryForEvent(EventEvidenceValidatedValue)
+	EventQueryMempoolTxAdded      = QueryForEvent(EventMempoolTxAddedValue)
+	EventQueryMempoolTxRemoved    = QueryForEvent(EventMempoolTxRemovedValue)
 )
 
 func EventQueryTxFor(tx Tx) *tmquery.Query {
@@ -508,6 +546,12 @@ type TxEventPublisher interface {
 	PublishEventTx(EventDataTx) error
 }
 
+// MempoolEventPublisher publishes the mempool's transaction events
+type MempoolEventPublisher interface {
+	PublishEventMempoolTxAdded(EventDataMempoolTx) error
+	PublishEventMempoolTxRemoved(EventDataMempoolTx) error
+}
+
 // eventWithAttr constructs a single abci.Event with a single attribute.
 // The type of the event and the name of the attribute are obtained by
 // splitting the event type on period (e.g., "foo.bar").
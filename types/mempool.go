@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"time"
 
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 )
@@ -16,17 +17,9 @@ type TxKey [sha256.Size]byte
 
 // ToProto converts Data to protobuf
 func (txKey *TxKey) ToProto() *tmproto.TxKey {
-	tp := new(tmproto.TxKey)
-
 	txBzs := make([]byte, len(txKey))
-	if len(txKey) > 0 {
-		for i := range txKey {
-			txBzs[i] = txKey[i]
-		}
-		tp.TxKey = txBzs
-	}
-
-	return tp
+	copy(txBzs, txKey[:])
+	return &tmproto.TxKey{TxKey: txBzs}
 }
 
 // TxKeyFromProto takes a protobuf representation of TxKey &
@@ -35,16 +28,20 @@ func TxKeyFromProto(dp *tmproto.TxKey) (TxKey, error) {
 	if dp == nil {
 		return TxKey{}, errors.New("nil data")
 	}
-	var txBzs [sha256.Size]byte
-	for i := range dp.TxKey {
-		txBzs[i] = dp.TxKey[i]
+	if len(dp.TxKey) > sha256.Size {
+		return TxKey{}, fmt.Errorf("tx key too long: %d bytes (max: %d)", len(dp.TxKey), sha256.Size)
 	}
+	var txKey TxKey
+	copy(txKey[:], dp.TxKey)
 
-	return txBzs, nil
+	return txKey, nil
 }
 
 func TxKeysListFromProto(dps []*tmproto.TxKey) ([]TxKey, error) {
-	var txKeys []TxKey
+	if len(dps) == 0 {
+		return nil, nil
+	}
+	txKeys := make([]TxKey, 0, len(dps))
 	for _, txKey := range dps {
 		txKey, err := TxKeyFromProto(txKey)
 		if err != nil {
@@ -73,16 +70,25 @@ type ErrMempoolIsFull struct {
 	MaxTxs      int
 	TxsBytes    int64
 	MaxTxsBytes int64
+
+	// MemoryBytes and MaxMemoryBytes are only set if the mempool's memory is
+	// limited.
+	MemoryBytes    int64
+	MaxMemoryBytes int64
 }
 
 func (e ErrMempoolIsFull) Error() string {
-	return fmt.Sprintf(
+	msg := fmt.Sprintf(
 		"mempool is full: number of txs %d (max: %d), total txs bytes %d (max: %d)",
 		e.NumTxs,
 		e.MaxTxs,
 		e.TxsBytes,
 		e.MaxTxsBytes,
 	)
+	if e.MaxMemoryBytes > 0 {
+		msg += fmt.Sprintf(", memory bytes %d (max: %d)", e.MemoryBytes, e.MaxMemoryBytes)
+	}
+	return msg
 }
 
 // ErrMempoolPendingIsFull defines an error where there are too many pending transactions
@@ -92,16 +98,61 @@ type ErrMempoolPendingIsFull struct {
 	MaxTxs      int
 	TxsBytes    int64
 	MaxTxsBytes int64
+
+	// MemoryBytes and MaxMemoryBytes are only set if the mempool's memory is
+	// limited.
+	MemoryBytes    int64
+	MaxMemoryBytes int64
 }
 
 func (e ErrMempoolPendingIsFull) Error() string {
-	return fmt.Sprintf(
+	msg := fmt.Sprintf(
 		"mempool pending set is full: number of txs %d (max: %d), total txs bytes %d (max: %d)",
 		e.NumTxs,
 		e.MaxTxs,
 		e.TxsBytes,
 		e.MaxTxsBytes,
 	)
+	if e.MaxMemoryBytes > 0 {
+		msg += fmt.Sprintf(", memory bytes %d (max: %d)", e.MemoryBytes, e.MaxMemoryBytes)
+	}
+	return msg
+}
+
+// ErrRateLimited defines an error where a peer, or the RPC if PeerID is empty,
+// has exceeded its mempool submission quota for either transactions or bytes.
+type ErrRateLimited struct {
+	PeerID NodeID
+	Quota  string
+}
+
+func (e ErrRateLimited) Error() string {
+	if e.PeerID == "" {
+		return fmt.Sprintf("rpc submissions exceeded the mempool %s rate limit", e.Quota)
+	}
+	return fmt.Sprintf("peer %s exceeded the mempool %s rate limit", e.PeerID, e.Quota)
+}
+
+// ErrAppOverloaded defines an error where the application is too slow to
+// execute CheckTx, or fails to, and the mempool sheds load until it recovers.
+type ErrAppOverloaded struct {
+	Latency   time.Duration
+	Threshold time.Duration
+
+	// Failure is set instead of Latency if the mempool sheds load because a
+	// CheckTx call failed.
+	Failure error
+}
+
+func (e ErrAppOverloaded) Error() string {
+	if e.Failure != nil {
+		return fmt.Sprintf("application is overloaded: CheckTx failed: %v, try again later", e.Failure)
+	}
+	return fmt.Sprintf(
+		"application is overloaded: CheckTx latency %s (threshold: %s), try again later",
+		e.Latency,
+		e.Threshold,
+	)
 }
 
 // ErrPreCheck defines an error where a transaction fails a pre-check.
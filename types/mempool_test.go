@@ -0,0 +1,62 @@
+package types
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+)
+
+func TestTxKeyProto(t *testing.T) {
+	key := Tx("tx").Key()
+
+	pb := key.ToProto()
+	require.Equal(t, key[:], pb.TxKey)
+
+	// the proto must not alias the key
+	pb.TxKey[0]++
+	require.NotEqual(t, key[:], pb.TxKey)
+	pb.TxKey[0]--
+
+	got, err := TxKeyFromProto(pb)
+	require.NoError(t, err)
+	require.Equal(t, key, got)
+
+	_, err = TxKeyFromProto(nil)
+	require.Error(t, err)
+	_, err = TxKeyFromProto(&tmproto.TxKey{TxKey: make([]byte, sha256.Size+1)})
+	require.Error(t, err)
+
+	keys, err := TxKeysListFromProto(nil)
+	require.NoError(t, err)
+	require.Nil(t, keys)
+}
+
+var sinkTxKeyProto *tmproto.TxKey
+
+func BenchmarkTxKeyToProto(b *testing.B) {
+	key := Tx("tx").Key()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sinkTxKeyProto = key.ToProto()
+	}
+}
+
+func BenchmarkTxKeysListFromProto(b *testing.B) {
+	pbs := make([]*tmproto.TxKey, 1000)
+	for i := range pbs {
+		key := Tx(fmt.Sprintf("tx-%d", i)).Key()
+		pbs[i] = key.ToProto()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = TxKeysListFromProto(pbs)
+	}
+}